@@ -2,15 +2,26 @@ package mssql
 
 const (
   serverProp               = "server"
+  serverNameProp           = "server_name"
   databaseProp             = "database"
   principalIdProp          = "principal_id"
   usernameProp             = "username"
   objectIdProp             = "object_id"
   passwordProp             = "password"
+  passwordHashProp         = "password_hash"
   sidStrProp               = "sid"
   clientIdProp             = "client_id"
   authenticationTypeProp   = "authentication_type"
   defaultSchemaProp        = "default_schema"
   defaultSchemaPropDefault = "dbo"
   rolesProp                = "roles"
+  createMissingRolesProp   = "create_missing_roles"
+  reassignOwnedToProp      = "reassign_owned_to"
+  modifyDateProp           = "modify_date"
+  trackModificationsProp   = "track_modifications"
+  foundProp                = "found"
+  failIfMissingProp        = "fail_if_missing"
+  verifyAccessProp         = "verify_access"
+  lastAppliedSqlProp       = "last_applied_sql"
+  tagsProp                 = "tags"
 )
@@ -0,0 +1,136 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const filesProp = "files"
+
+type DatabaseFilesConnector interface {
+	GetDatabaseFiles(ctx context.Context, database string) ([]model.DatabaseFile, error)
+}
+
+func dataSourceDatabaseFiles() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDatabaseFilesRead,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			filesProp: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"file_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						nameProp: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type_desc": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"physical_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size_mb": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"max_size_mb": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"growth": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"is_percent_growth": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"used_space_mb": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"free_space_mb": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func dataSourceDatabaseFilesRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := meta.(model.Provider).DataSourceLogger("database_files", "read")
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	provider := meta.(model.Provider)
+	c, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	connector := c.(DatabaseFilesConnector)
+
+	files, err := connector.GetDatabaseFiles(ctx, database)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read files of database [%s]", database))
+	}
+	logger.Debug().Msgf("Read %d files of database [%s]", len(files), database)
+
+	result := make([]map[string]interface{}, len(files))
+	for i, file := range files {
+		result[i] = map[string]interface{}{
+			"file_id":           file.FileID,
+			nameProp:            file.Name,
+			"type_desc":         file.TypeDesc,
+			"physical_name":     file.PhysicalName,
+			"size_mb":           file.SizeMB,
+			"max_size_mb":       file.MaxSizeMB,
+			"growth":            file.Growth,
+			"is_percent_growth": file.IsPercentGrowth,
+			"used_space_mb":     file.UsedSpaceMB,
+			"free_space_mb":     file.SizeMB - file.UsedSpaceMB,
+		}
+	}
+	if err = data.Set(filesProp, result); err != nil {
+		return diag.FromErr(err)
+	}
+
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	data.SetId(fmt.Sprintf("sqlserver://%s:%s/%s/files", host, port, database))
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceDatabaseFiles_Local(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceDatabaseFiles(t, "master", map[string]interface{}{"database": "master"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_database_files.master", "files.#", "2"),
+					resource.TestCheckResourceAttr("data.mssql_database_files.master", "files.0.name", "master"),
+					resource.TestCheckResourceAttr("data.mssql_database_files.master", "files.0.type_desc", "ROWS"),
+					resource.TestCheckResourceAttr("data.mssql_database_files.master", "files.1.type_desc", "LOG"),
+					resource.TestCheckResourceAttrSet("data.mssql_database_files.master", "files.0.size_mb"),
+					resource.TestCheckResourceAttrSet("data.mssql_database_files.master", "files.0.used_space_mb"),
+				),
+			},
+			{
+				Config:      testAccCheckDataSourceDatabaseFiles(t, "missing", map[string]interface{}{"database": "no_such_database"}),
+				ExpectError: regexp.MustCompile("database \\[no_such_database\\] does not exist"),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceDatabaseFiles(t *testing.T, name string, data map[string]interface{}) string {
+	text := `data "mssql_database_files" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
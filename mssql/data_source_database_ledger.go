@@ -0,0 +1,150 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const verifyProp = "verify"
+const digestLocationsProp = "digest_locations"
+const verificationStatusProp = "verification_status"
+const verificationMessageProp = "verification_message"
+
+type DatabaseLedgerConnector interface {
+	GetDatabaseLedger(ctx context.Context, database string) (*model.DatabaseLedger, error)
+	VerifyDatabaseLedger(ctx context.Context, database string) (string, error)
+}
+
+func dataSourceDatabaseLedger() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDatabaseLedgerRead,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			verifyProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"is_ledger_on": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			digestLocationsProp: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_digest_block_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"is_current": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+			verificationStatusProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			verificationMessageProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func dataSourceDatabaseLedgerRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := meta.(model.Provider).DataSourceLogger("database_ledger", "read")
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	provider := meta.(model.Provider)
+	c, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	connector := c.(DatabaseLedgerConnector)
+
+	ledger, err := connector.GetDatabaseLedger(ctx, database)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read ledger of database [%s]", database))
+	}
+	logger.Debug().Msgf("Read ledger of database [%s]: is_ledger_on %t, %d digest locations", database, ledger.IsLedgerOn, len(ledger.DigestLocations))
+
+	locations := make([]map[string]interface{}, len(ledger.DigestLocations))
+	for i, location := range ledger.DigestLocations {
+		locations[i] = map[string]interface{}{
+			"path":                 location.Path,
+			"last_digest_block_id": location.LastDigestBlockID,
+			"is_current":           location.IsCurrent,
+		}
+	}
+	if err = data.Set("is_ledger_on", ledger.IsLedgerOn); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(digestLocationsProp, locations); err != nil {
+		return diag.FromErr(err)
+	}
+
+	status, message := "", ""
+	if data.Get(verifyProp).(bool) {
+		if !ledger.IsLedgerOn {
+			return diag.Errorf("database [%s] is not a ledger database, so its ledger cannot be verified", database)
+		}
+		if len(ledger.DigestLocations) == 0 {
+			return diag.Errorf("database [%s] has no digest locations to verify its ledger against; enable automatic digest storage", database)
+		}
+		message, err = connector.VerifyDatabaseLedger(ctx, database)
+		if err != nil {
+			return diag.FromErr(errors.Wrapf(err, "unable to verify ledger of database [%s]", database))
+		}
+		status = "Passed"
+		if message != "" {
+			status = "Failed"
+		}
+		logger.Info().Msgf("Verification of the ledger of database [%s] %s", database, status)
+	}
+	if err = data.Set(verificationStatusProp, status); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(verificationMessageProp, message); err != nil {
+		return diag.FromErr(err)
+	}
+
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	data.SetId(fmt.Sprintf("sqlserver://%s:%s/%s/ledger", host, port, database))
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceDatabaseLedger_Local(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceDatabaseLedger(t, "master", map[string]interface{}{"database": "master", "verify": false}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_database_ledger.master", "is_ledger_on", "false"),
+					resource.TestCheckResourceAttr("data.mssql_database_ledger.master", "digest_locations.#", "0"),
+					resource.TestCheckResourceAttr("data.mssql_database_ledger.master", "verification_status", ""),
+				),
+			},
+			{
+				Config:      testAccCheckDataSourceDatabaseLedger(t, "verify", map[string]interface{}{"database": "master", "verify": true}),
+				ExpectError: regexp.MustCompile("database \\[master\\] is not a ledger database"),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceDatabaseLedger(t *testing.T, name string, data map[string]interface{}) string {
+	text := `data "mssql_database_ledger" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             verify   = {{ .verify }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
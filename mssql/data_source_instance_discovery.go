@@ -0,0 +1,151 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const servicesProp = "services"
+
+type InstanceConnector interface {
+	GetInstance(ctx context.Context) (*model.Instance, error)
+}
+
+func dataSourceInstanceDiscovery() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceInstanceDiscoveryRead,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			serverNameProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"machine_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"instance_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"edition": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"engine_edition": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"product_version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"collation": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_clustered": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"is_hadr_enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"default_data_path": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_log_path": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			servicesProp: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						nameProp: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"service_account": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func dataSourceInstanceDiscoveryRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := meta.(model.Provider).DataSourceLogger("instance_discovery", "read")
+
+	provider := meta.(model.Provider)
+	c, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	connector := c.(InstanceConnector)
+
+	instance, err := connector.GetInstance(ctx)
+	if err != nil {
+		return diag.FromErr(errors.Wrap(err, "unable to read instance properties"))
+	}
+	logger.Debug().Msgf("Read instance [%s], engine edition %d", instance.ServerName, instance.EngineEdition)
+
+	services := make([]map[string]interface{}, len(instance.Services))
+	for i, service := range instance.Services {
+		services[i] = map[string]interface{}{
+			nameProp:          service.Name,
+			"service_account": service.ServiceAccount,
+			"status":          service.Status,
+		}
+	}
+	values := map[string]interface{}{
+		serverNameProp:      instance.ServerName,
+		"machine_name":      instance.MachineName,
+		"instance_name":     instance.InstanceName,
+		"edition":           instance.Edition,
+		"engine_edition":    instance.EngineEdition,
+		"product_version":   instance.ProductVersion,
+		"collation":         instance.Collation,
+		"is_clustered":      instance.IsClustered,
+		"is_hadr_enabled":   instance.IsHadrEnabled,
+		"default_data_path": instance.DefaultDataPath,
+		"default_log_path":  instance.DefaultLogPath,
+		servicesProp:        services,
+	}
+	for key, value := range values {
+		if err = data.Set(key, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	data.SetId(fmt.Sprintf("sqlserver://%s:%s/instance", host, port))
+
+	return nil
+}
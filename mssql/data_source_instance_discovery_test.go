@@ -0,0 +1,42 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceInstanceDiscovery_Local(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceInstanceDiscovery(t, "basic", map[string]interface{}{}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_instance_discovery.basic", "instance_name", ""),
+					resource.TestCheckResourceAttr("data.mssql_instance_discovery.basic", "is_clustered", "false"),
+					resource.TestMatchResourceAttr("data.mssql_instance_discovery.basic", "collation", regexp.MustCompile(`.+`)),
+					resource.TestMatchResourceAttr("data.mssql_instance_discovery.basic", "default_data_path", regexp.MustCompile(`^/var/opt/mssql/data/?$`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceInstanceDiscovery(t *testing.T, name string, data map[string]interface{}) string {
+	text := `data "mssql_instance_discovery" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
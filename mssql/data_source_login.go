@@ -0,0 +1,102 @@
+package mssql
+
+import (
+	"context"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+func dataSourceLogin() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLoginRead,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			loginNameProp: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			failIfMissingProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			foundProp: {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			loginTypeProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			defaultDatabaseProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			defaultLanguageProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			principalIdProp: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func dataSourceLoginRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := meta.(model.Provider).DataSourceLogger("login", "read")
+
+	loginName := data.Get(loginNameProp).(string)
+
+	connector, err := getLoginConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	login, err := connector.GetLogin(ctx, loginName)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read login [%s]", loginName))
+	}
+	found := login != nil
+	if !found {
+		if data.Get(failIfMissingProp).(bool) {
+			return diag.Errorf("no login [%s] found", loginName)
+		}
+		logger.Debug().Msgf("No login found for [%s]", loginName)
+		login = &model.Login{}
+	}
+
+	if err = data.Set(foundProp, found); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(loginTypeProp, login.LoginType); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(defaultDatabaseProp, login.DefaultDatabase); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(defaultLanguageProp, login.DefaultLanguage); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(principalIdProp, login.PrincipalID); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getLoginID(data))
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceLogin_Local(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceLogin(t, "sa", map[string]interface{}{"login_name": "sa"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_login.sa", "found", "true"),
+					resource.TestCheckResourceAttr("data.mssql_login.sa", "login_type", "SQL"),
+					resource.TestCheckResourceAttr("data.mssql_login.sa", "principal_id", "1"),
+				),
+			},
+			{
+				Config: testAccCheckDataSourceLogin(t, "missing", map[string]interface{}{"login_name": "missing_login", "fail_if_missing": "false"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_login.missing", "found", "false"),
+				),
+			},
+			{
+				Config:      testAccCheckDataSourceLogin(t, "strict", map[string]interface{}{"login_name": "missing_login"}),
+				ExpectError: regexp.MustCompile("no login \\[missing_login\\] found"),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceLogin(t *testing.T, name string, data map[string]interface{}) string {
+	text := `data "mssql_login" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             login_name = "{{ .login_name }}"
+             {{ with .fail_if_missing }}fail_if_missing = {{ . }}{{ end }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
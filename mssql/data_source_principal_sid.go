@@ -0,0 +1,113 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const nameProp = "name"
+
+type PrincipalConnector interface {
+	GetServerPrincipal(ctx context.Context, name, sid string) (*model.Principal, error)
+	GetDatabasePrincipal(ctx context.Context, database, name, sid string) (*model.Principal, error)
+}
+
+func dataSourcePrincipalSID() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePrincipalSIDRead,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			nameProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{nameProp, sidStrProp},
+			},
+			sidStrProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{nameProp, sidStrProp},
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^0x[0-9A-Fa-f]+$`), "must be a hex string starting with 0x"),
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func dataSourcePrincipalSIDRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := meta.(model.Provider).DataSourceLogger("principal_sid", "read")
+
+	database := data.Get(databaseProp).(string)
+	name := data.Get(nameProp).(string)
+	sid := data.Get(sidStrProp).(string)
+
+	connector, err := getPrincipalConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var principal *model.Principal
+	if database == "" {
+		principal, err = connector.GetServerPrincipal(ctx, name, sid)
+	} else {
+		principal, err = connector.GetDatabasePrincipal(ctx, database, name, sid)
+	}
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read principal [%s%s]", name, sid))
+	}
+	if principal == nil {
+		if database == "" {
+			return diag.Errorf("no server principal [%s%s] found", name, sid)
+		}
+		return diag.Errorf("no principal [%s%s] found in database [%s]", name, sid, database)
+	}
+
+	logger.Debug().Msgf("Resolved principal [%s] with SID %s", principal.Name, principal.SIDStr)
+
+	if err = data.Set(nameProp, principal.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(sidStrProp, principal.SIDStr); err != nil {
+		return diag.FromErr(err)
+	}
+
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	if database == "" {
+		data.SetId(fmt.Sprintf("sqlserver://%s:%s/%s", host, port, principal.SIDStr))
+	} else {
+		data.SetId(fmt.Sprintf("sqlserver://%s:%s/%s/%s", host, port, database, principal.SIDStr))
+	}
+
+	return nil
+}
+
+func getPrincipalConnector(meta interface{}, data *schema.ResourceData) (PrincipalConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(PrincipalConnector), nil
+}
@@ -0,0 +1,69 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPrincipalSID_Local_Server(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPrincipalSID(t, "by_name", map[string]interface{}{"name": "sa"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_principal_sid.by_name", "name", "sa"),
+					resource.TestCheckResourceAttr("data.mssql_principal_sid.by_name", "sid", "0x01"),
+				),
+			},
+			{
+				Config: testAccCheckPrincipalSID(t, "by_sid", map[string]interface{}{"sid": "0x01"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_principal_sid.by_sid", "name", "sa"),
+					resource.TestCheckResourceAttr("data.mssql_principal_sid.by_sid", "sid", "0x01"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPrincipalSID_Local_Database(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPrincipalSID(t, "dbo", map[string]interface{}{"database": "master", "name": "dbo"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_principal_sid.dbo", "name", "dbo"),
+					resource.TestCheckResourceAttr("data.mssql_principal_sid.dbo", "sid", "0x01"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPrincipalSID(t *testing.T, name string, data map[string]interface{}) string {
+	text := `data "mssql_principal_sid" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             {{ with .database }}database = "{{ . }}"{{ end }}
+             {{ with .principal }}name = "{{ . }}"{{ end }}
+             {{ with .sid }}sid = "{{ . }}"{{ end }}
+           }`
+	if principal, ok := data["name"]; ok {
+		data["principal"] = principal
+	}
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
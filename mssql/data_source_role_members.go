@@ -0,0 +1,134 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const roleNameProp = "role_name"
+const membersProp = "members"
+
+type RoleMembersConnector interface {
+	GetRoleMembers(ctx context.Context, database, role string) ([]model.RoleMember, error)
+}
+
+func dataSourceRoleMembers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRoleMembersRead,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			roleNameProp: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			membersProp: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						nameProp: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						principalIdProp: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						sidStrProp: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						loginNameProp: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_role": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func dataSourceRoleMembersRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := meta.(model.Provider).DataSourceLogger("role_members", "read")
+
+	database := data.Get(databaseProp).(string)
+	role := data.Get(roleNameProp).(string)
+
+	if database != "" {
+		if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	provider := meta.(model.Provider)
+	c, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	connector := c.(RoleMembersConnector)
+
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	id := fmt.Sprintf("sqlserver://%s:%s/role_members/%s", host, port, role)
+	description := fmt.Sprintf("server role [%s]", role)
+	if database != "" {
+		id = fmt.Sprintf("sqlserver://%s:%s/%s/role_members/%s", host, port, database, role)
+		description = fmt.Sprintf("role [%s].[%s]", database, role)
+	}
+
+	members, err := connector.GetRoleMembers(ctx, database, role)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read members of %s", description))
+	}
+	if members == nil {
+		return diag.Errorf("%s does not exist", description)
+	}
+	logger.Debug().Msgf("Read %d members of %s", len(members), description)
+
+	result := make([]map[string]interface{}, len(members))
+	for i, member := range members {
+		result[i] = map[string]interface{}{
+			nameProp:        member.Name,
+			principalIdProp: member.PrincipalID,
+			"type":          member.TypeDesc,
+			sidStrProp:      member.SIDStr,
+			loginNameProp:   member.LoginName,
+			"is_role":       member.IsRole,
+		}
+	}
+	if err = data.Set(membersProp, result); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(id)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRoleMembers_Local(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceRoleMembers(t, "sysadmin", map[string]interface{}{"role_name": "sysadmin"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.mssql_role_members.sysadmin", "members.*", map[string]string{
+						"name":       "sa",
+						"type":       "SQL_LOGIN",
+						"login_name": "sa",
+						"is_role":    "false",
+					}),
+				),
+			},
+			{
+				Config: testAccCheckDataSourceRoleMembers(t, "db_owner", map[string]interface{}{"database": "master", "role_name": "db_owner"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.mssql_role_members.db_owner", "members.*", map[string]string{
+						"name":       "dbo",
+						"login_name": "sa",
+						"is_role":    "false",
+					}),
+				),
+			},
+			{
+				Config: testAccCheckDataSourceRoleMembers(t, "empty", map[string]interface{}{"database": "master", "role_name": "db_denydatareader"}),
+				Check:  resource.TestCheckResourceAttr("data.mssql_role_members.empty", "members.#", "0"),
+			},
+			{
+				Config:      testAccCheckDataSourceRoleMembers(t, "missing", map[string]interface{}{"database": "master", "role_name": "no_such_role"}),
+				ExpectError: regexp.MustCompile("role \\[master\\]\\.\\[no_such_role\\] does not exist"),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceRoleMembers(t *testing.T, name string, data map[string]interface{}) string {
+	text := `data "mssql_role_members" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             {{ with .database }}database = "{{ . }}"{{ end }}
+             role_name = "{{ .role_name }}"
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
@@ -0,0 +1,153 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const includeRolesProp = "include_roles"
+const includePermissionsProp = "include_permissions"
+const principalsProp = "principals"
+
+type ServerPrincipalsConnector interface {
+	GetServerPrincipals(ctx context.Context, includeRoles, includePermissions bool) ([]*model.ServerPrincipal, error)
+}
+
+func dataSourceServerPrincipals() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServerPrincipalsRead,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			includeRolesProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			includePermissionsProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			principalsProp: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						nameProp: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						principalIdProp: {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						sidStrProp: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_disabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						defaultDatabaseProp: {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						rolesProp: {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"permissions": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"permission": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"state": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func dataSourceServerPrincipalsRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := meta.(model.Provider).DataSourceLogger("server_principals", "read")
+
+	includeRoles := data.Get(includeRolesProp).(bool)
+	includePermissions := data.Get(includePermissionsProp).(bool)
+
+	provider := meta.(model.Provider)
+	c, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	connector := c.(ServerPrincipalsConnector)
+
+	principals, err := connector.GetServerPrincipals(ctx, includeRoles, includePermissions)
+	if err != nil {
+		return diag.FromErr(errors.Wrap(err, "unable to read server principals"))
+	}
+	logger.Debug().Msgf("Read %d server principals", len(principals))
+
+	result := make([]map[string]interface{}, len(principals))
+	for i, principal := range principals {
+		permissions := make([]map[string]interface{}, len(principal.Permissions))
+		for j, permission := range principal.Permissions {
+			permissions[j] = map[string]interface{}{
+				"permission": permission.Permission,
+				"state":      permission.State,
+			}
+		}
+		result[i] = map[string]interface{}{
+			nameProp:            principal.Name,
+			principalIdProp:     principal.PrincipalID,
+			"type":              principal.Type,
+			sidStrProp:          principal.SIDStr,
+			"is_disabled":       principal.IsDisabled,
+			defaultDatabaseProp: principal.DefaultDatabase,
+			rolesProp:           principal.Roles,
+			"permissions":       permissions,
+		}
+	}
+	if err = data.Set(principalsProp, result); err != nil {
+		return diag.FromErr(err)
+	}
+
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	data.SetId(fmt.Sprintf("sqlserver://%s:%s/server_principals", host, port))
+
+	return nil
+}
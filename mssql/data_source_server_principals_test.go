@@ -0,0 +1,56 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceServerPrincipals_Local(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceServerPrincipals(t, "basic", map[string]interface{}{}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.mssql_server_principals.basic", "principals.*", map[string]string{
+						"name":          "sa",
+						"type":          "SQL_LOGIN",
+						"roles.#":       "0",
+						"permissions.#": "0",
+					}),
+				),
+			},
+			{
+				Config: testAccCheckDataSourceServerPrincipals(t, "full", map[string]interface{}{"include_roles": "true", "include_permissions": "true"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.mssql_server_principals.full", "principals.*", map[string]string{
+						"name":                     "sa",
+						"roles.0":                  "sysadmin",
+						"permissions.0.permission": "CONNECT SQL",
+						"permissions.0.state":      "GRANT",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceServerPrincipals(t *testing.T, name string, data map[string]interface{}) string {
+	text := `data "mssql_server_principals" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             {{ with .include_roles }}include_roles = {{ . }}{{ end }}
+             {{ with .include_permissions }}include_permissions = {{ . }}{{ end }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
@@ -0,0 +1,136 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+func dataSourceUser() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceUserRead,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "master",
+			},
+			usernameProp: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			failIfMissingProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			foundProp: {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			loginNameProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			sidStrProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			authenticationTypeProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			principalIdProp: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			defaultSchemaProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			defaultLanguageProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			rolesProp: {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func dataSourceUserRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := meta.(model.Provider).DataSourceLogger("user", "read")
+
+	database := data.Get(databaseProp).(string)
+	username := data.Get(usernameProp).(string)
+
+	connector, err := getUserConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user, err := connector.GetUser(ctx, database, username)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read user [%s].[%s]", database, username))
+	}
+	found := user != nil
+	if !found {
+		if data.Get(failIfMissingProp).(bool) {
+			return diag.Errorf("no user [%s] found in database [%s]", username, database)
+		}
+		logger.Debug().Msgf("No user found for [%s].[%s]", database, username)
+		user = &model.User{}
+	}
+
+	if err = data.Set(foundProp, found); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(loginNameProp, user.LoginName); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(sidStrProp, user.SIDStr); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(authenticationTypeProp, user.AuthType); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(principalIdProp, user.PrincipalID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(defaultSchemaProp, user.DefaultSchema); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(defaultLanguageProp, user.DefaultLanguage); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(rolesProp, user.Roles); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The user may not exist, so it is named rather than identified by its principal id as the resource is
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	data.SetId(fmt.Sprintf("sqlserver://%s:%s/%s/%s", host, port, database, username))
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceUser_Local(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceUser(t, "dbo", map[string]interface{}{"username": "dbo"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_user.dbo", "found", "true"),
+					resource.TestCheckResourceAttr("data.mssql_user.dbo", "database", "master"),
+					resource.TestCheckResourceAttr("data.mssql_user.dbo", "sid", "0x01"),
+				),
+			},
+			{
+				Config: testAccCheckDataSourceUser(t, "missing", map[string]interface{}{"username": "missing_user", "fail_if_missing": "false"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mssql_user.missing", "found", "false"),
+				),
+			},
+			{
+				Config:      testAccCheckDataSourceUser(t, "strict", map[string]interface{}{"username": "missing_user"}),
+				ExpectError: regexp.MustCompile("no user \\[missing_user\\] found in database \\[master\\]"),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceUser(t *testing.T, name string, data map[string]interface{}) string {
+	text := `data "mssql_user" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             {{ with .database }}database = "{{ . }}"{{ end }}
+             username = "{{ .username }}"
+             {{ with .fail_if_missing }}fail_if_missing = {{ . }}{{ end }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
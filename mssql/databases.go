@@ -0,0 +1,77 @@
+package mssql
+
+import (
+  "context"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+  "strings"
+  "sync"
+)
+
+type DatabaseConnector interface {
+  GetDatabases(ctx context.Context) ([]string, error)
+}
+
+type DatabaseUpdateabilityConnector interface {
+  GetDatabaseUpdateability(ctx context.Context, database string) (string, error)
+}
+
+// databaseCache remembers the databases found on each server, so resources in the same few databases only list them once.
+type databaseCache struct {
+  mu        sync.Mutex
+  databases map[string]map[string]bool
+}
+
+func newDatabaseCache() *databaseCache {
+  return &databaseCache{databases: make(map[string]map[string]bool)}
+}
+
+func (c *databaseCache) contains(server, database string) bool {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  return c.databases[server][strings.ToLower(database)]
+}
+
+func (c *databaseCache) set(server string, databases []string) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  names := make(map[string]bool, len(databases))
+  for _, name := range databases {
+    names[strings.ToLower(name)] = true
+  }
+  c.databases[server] = names
+}
+
+func (p mssqlProvider) DatabaseExists(ctx context.Context, prefix string, data *schema.ResourceData, database string) (bool, error) {
+  if database == "" {
+    database = "master"
+  }
+  server := serverKey(prefix, data)
+  if p.databases.contains(server, database) {
+    return true, nil
+  }
+
+  // Reload on a miss, as the database may have been created since the cache was filled.
+  connector, err := p.GetConnector(prefix, data)
+  if err != nil {
+    return false, err
+  }
+  databases, err := connector.(DatabaseConnector).GetDatabases(ctx)
+  if err != nil {
+    p.logger.Warn().Err(err).Msgf("unable to list databases on %s, skipping validation of [%s]", server, database)
+    return true, nil
+  }
+  if databases == nil {
+    // The server does not expose its databases on this connection
+    return true, nil
+  }
+  p.databases.set(server, databases)
+
+  return p.databases.contains(server, database), nil
+}
+
+func serverKey(prefix string, data *schema.ResourceData) string {
+  if len(prefix) > 0 {
+    prefix = prefix + ".0."
+  }
+  return strings.ToLower(data.Get(prefix+"host").(string)) + ":" + data.Get(prefix+"port").(string)
+}
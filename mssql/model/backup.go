@@ -0,0 +1,20 @@
+package model
+
+type Backup struct {
+  Database   string
+  Url        string
+  Disk       string
+  Credential string
+  CopyOnly   bool
+  FinishDate string
+  LastLSN    string
+}
+
+type Restore struct {
+  Database    string
+  Url         string
+  Disk        string
+  Credential  string
+  Replace     bool
+  RestoreDate string
+}
@@ -0,0 +1,8 @@
+package model
+
+type ChangeTracking struct {
+  Database             string
+  RetentionPeriod      int
+  RetentionPeriodUnits string
+  AutoCleanup          bool
+}
@@ -0,0 +1,7 @@
+package model
+
+type Configuration struct {
+  Name       string
+  Value      int64
+  ValueInUse int64
+}
@@ -0,0 +1,13 @@
+package model
+
+type DatabaseFile struct {
+  FileID          int64
+  Name            string
+  TypeDesc        string
+  PhysicalName    string
+  SizeMB          float64
+  MaxSizeMB       float64
+  Growth          float64
+  IsPercentGrowth bool
+  UsedSpaceMB     float64
+}
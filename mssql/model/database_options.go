@@ -0,0 +1,10 @@
+package model
+
+type DatabaseOptions struct {
+  AutoClose                     bool
+  PageVerify                    string
+  DataRetention                 bool
+  DefaultFilegroup              string
+  FilestreamNonTransactedAccess string
+  FilestreamDirectoryName       string
+}
@@ -0,0 +1,16 @@
+package model
+
+type ElasticJobTargetGroup struct {
+  Name    string
+  Members []ElasticJobTargetGroupMember
+}
+
+type ElasticJobTargetGroupMember struct {
+  TargetID              string
+  MembershipType        string
+  TargetType            string
+  ServerName            string
+  DatabaseName          string
+  ElasticPoolName       string
+  RefreshCredentialName string
+}
@@ -0,0 +1,22 @@
+package model
+
+type Instance struct {
+  ServerName      string
+  MachineName     string
+  InstanceName    string
+  Edition         string
+  EngineEdition   int
+  ProductVersion  string
+  Collation       string
+  IsClustered     bool
+  IsHadrEnabled   bool
+  DefaultDataPath string
+  DefaultLogPath  string
+  Services        []InstanceService
+}
+
+type InstanceService struct {
+  Name           string
+  ServiceAccount string
+  Status         string
+}
@@ -0,0 +1,12 @@
+package model
+
+type DatabaseLedger struct {
+  IsLedgerOn      bool
+  DigestLocations []LedgerDigestLocation
+}
+
+type LedgerDigestLocation struct {
+  Path              string
+  LastDigestBlockID int64
+  IsCurrent         bool
+}
@@ -3,6 +3,17 @@ package model
 type Login struct {
   PrincipalID     int64
   LoginName       string
+  LoginType       string
+  Password        string
+  PasswordHashed  bool
+  CheckPolicy     bool
+  CheckExpiration bool
+  MustChange      bool
+  ObjectId        string
   DefaultDatabase string
   DefaultLanguage string
+  PasswordHash    string
+  SIDStr          string
+  ModifyDate      string
+  ServerName      string
 }
@@ -0,0 +1,7 @@
+package model
+
+type LogonTrigger struct {
+  Name       string
+  IsDisabled bool
+  Definition string
+}
@@ -0,0 +1,7 @@
+package model
+
+type ModuleSignature struct {
+  Certificate string
+  // Modules are the modules signed by the certificate, as schema.name
+  Modules []string
+}
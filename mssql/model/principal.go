@@ -0,0 +1,6 @@
+package model
+
+type Principal struct {
+  Name   string
+  SIDStr string
+}
@@ -1,12 +1,16 @@
 package model
 
 import (
+  "context"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
   "github.com/rs/zerolog"
 )
 
 type Provider interface {
   GetConnector(prefix string, data *schema.ResourceData) (interface{}, error)
+  DatabaseExists(ctx context.Context, prefix string, data *schema.ResourceData, database string) (bool, error)
   ResourceLogger(resource, function string) zerolog.Logger
   DataSourceLogger(datasource, function string) zerolog.Logger
+  IgnoreMissingObjects() bool
+  DefaultSchema() string
 }
@@ -0,0 +1,10 @@
+package model
+
+type RoleMember struct {
+  PrincipalID int64
+  Name        string
+  TypeDesc    string
+  SIDStr      string
+  LoginName   string
+  IsRole      bool
+}
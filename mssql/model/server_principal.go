@@ -0,0 +1,17 @@
+package model
+
+type ServerPrincipal struct {
+  PrincipalID     int64
+  Name            string
+  Type            string
+  SIDStr          string
+  IsDisabled      bool
+  DefaultDatabase string
+  Roles           []string
+  Permissions     []ServerPermission
+}
+
+type ServerPermission struct {
+  Permission string
+  State      string
+}
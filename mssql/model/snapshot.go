@@ -0,0 +1,8 @@
+package model
+
+type DatabaseSnapshot struct {
+  Name       string
+  Database   string
+  Directory  string
+  CreateDate string
+}
@@ -11,4 +11,6 @@ type User struct {
   DefaultSchema   string
   DefaultLanguage string
   Roles           []string
+  ModifyDate      string
+  ServerName      string
 }
@@ -5,18 +5,37 @@ import (
   "fmt"
   "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
   "github.com/rs/zerolog"
   "github.com/rs/zerolog/log"
   "io"
   "os"
+  "strings"
   "github.com/betr-io/terraform-provider-mssql/mssql/model"
   "github.com/betr-io/terraform-provider-mssql/sql"
   "time"
 )
 
 type mssqlProvider struct {
-  factory model.ConnectorFactory
-  logger  *zerolog.Logger
+  factory   model.ConnectorFactory
+  logger    *zerolog.Logger
+  databases *databaseCache
+  // additionalTransientErrors are retried by the connectors in addition to their built-in transient errors
+  additionalTransientErrors []int32
+  // ignoreMissingObjects removes resources from state when a read fails because the object or database is gone
+  ignoreMissingObjects bool
+  // sessionLanguage and sessionDateFormat are set on every session of the connectors
+  sessionLanguage   string
+  sessionDateFormat string
+  // executeAsType and executeAsName are the principal every session of the connectors impersonates, if set
+  executeAsType string
+  executeAsName string
+  // defaultSchema is the schema unqualified names in the statements of mssql_raw_exec must resolve to, if set
+  defaultSchema string
+  // keepAlive is shared by the connectors, and is nil unless keepalive_interval is set
+  keepAlive *sql.KeepAlive
+  // warmup is nil unless connection_warmup is set
+  warmup *connectionWarmup
 }
 
 const (
@@ -34,7 +53,7 @@ func New(version, commit string) func() *schema.Provider {
 }
 
 func Provider(factory model.ConnectorFactory) *schema.Provider {
-  return &schema.Provider{
+  provider := &schema.Provider{
     Schema: map[string]*schema.Schema{
       "debug": {
         Type:        schema.TypeBool,
@@ -42,29 +61,215 @@ func Provider(factory model.ConnectorFactory) *schema.Provider {
         Optional:    true,
         Default:     false,
       },
+      "additional_transient_error_numbers": {
+        Type:        schema.TypeList,
+        Description: "Numbers of SQL errors to retry in addition to the built-in transient errors",
+        Optional:    true,
+        Elem: &schema.Schema{
+          Type: schema.TypeInt,
+        },
+      },
+      "ignore_missing_objects": {
+        Type:        schema.TypeBool,
+        Description: "Remove resources from state instead of failing when a read finds that the object or its database does not exist or cannot be opened",
+        Optional:    true,
+        Default:     false,
+      },
+      "session_language": {
+        Type:        schema.TypeString,
+        Description: "Language to SET on every session, instead of the default language of the login",
+        Optional:    true,
+      },
+      "dateformat": {
+        Type:         schema.TypeString,
+        Description:  "Order of month, day and year to SET as DATEFORMAT on every session",
+        Optional:     true,
+        ValidateFunc: validation.StringInSlice([]string{"mdy", "dmy", "ymd", "ydm", "myd", "dym"}, false),
+      },
+      "keepalive_interval": {
+        Type:         schema.TypeString,
+        Description:  "Interval at which to run SELECT 1 in each database used, e.g. 5m, to keep serverless databases from pausing during long runs",
+        Optional:     true,
+        ValidateFunc: validateKeepAliveInterval,
+      },
+      "default_schema": {
+        Type:         schema.TypeString,
+        Description:  "Schema that unqualified names in ad-hoc statements, such as those of mssql_raw_exec, must resolve to",
+        Optional:     true,
+        ValidateFunc: validation.StringIsNotWhiteSpace,
+      },
+      "connection_warmup": {
+        Type:        schema.TypeBool,
+        Description: "Connect to the server and database of each resource once during plan, to report connection and login problems with every target before apply",
+        Optional:    true,
+        Default:     false,
+      },
+      "execute_as": {
+        Type:        schema.TypeList,
+        Description: "Principal to impersonate with EXECUTE AS on every session, which the login of the provider needs IMPERSONATE permission on",
+        Optional:    true,
+        MaxItems:    1,
+        Elem: &schema.Resource{
+          Schema: map[string]*schema.Schema{
+            "name": {
+              Type:         schema.TypeString,
+              Description:  "Name of the login or user to impersonate",
+              Required:     true,
+              ValidateFunc: validation.StringIsNotWhiteSpace,
+            },
+            "type": {
+              Type:         schema.TypeString,
+              Description:  "Whether name is a LOGIN or a USER of the database",
+              Optional:     true,
+              Default:      "LOGIN",
+              ValidateFunc: validation.StringInSlice([]string{"LOGIN", "USER"}, false),
+            },
+          },
+        },
+      },
     },
     ResourcesMap: map[string]*schema.Resource{
-      "mssql_login": resourceLogin(),
-      "mssql_user":  resourceUser(),
+      "mssql_database_ansi_options":               resourceDatabaseAnsiOptions(),
+      "mssql_database_backup":                     resourceDatabaseBackup(),
+      "mssql_database_cdc":                        resourceDatabaseCDC(),
+      "mssql_database_change_tracking":            resourceDatabaseChangeTracking(),
+      "mssql_database_maxdop":                     resourceDatabaseMaxDop(),
+      "mssql_database_options":                    resourceDatabaseOptions(),
+      "mssql_database_restore":                    resourceDatabaseRestore(),
+      "mssql_database_scoped_configuration":       resourceDatabaseScopedConfiguration(),
+      "mssql_database_snapshot":                   resourceDatabaseSnapshot(),
+      "mssql_database_snapshot_revert":            resourceDatabaseSnapshotRevert(),
+      "mssql_database_state":                      resourceDatabaseState(),
+      "mssql_database_temporal_history_retention": resourceDatabaseTemporalHistoryRetention(),
+      "mssql_elastic_job_target_group":            resourceElasticJobTargetGroup(),
+      "mssql_login":                               resourceLogin(),
+      "mssql_logon_trigger":                       resourceLogonTrigger(),
+      "mssql_module_signature":                    resourceModuleSignature(),
+      "mssql_raw_exec":                            resourceRawExec(),
+      "mssql_server_configurations":               resourceServerConfigurations(),
+      "mssql_user":                                resourceUser(),
+      "mssql_users":                               resourceUsers(),
+    },
+    DataSourcesMap: map[string]*schema.Resource{
+      "mssql_database_files":     dataSourceDatabaseFiles(),
+      "mssql_database_ledger":    dataSourceDatabaseLedger(),
+      "mssql_instance_discovery": dataSourceInstanceDiscovery(),
+      "mssql_login":              dataSourceLogin(),
+      "mssql_principal_sid":      dataSourcePrincipalSID(),
+      "mssql_role_members":       dataSourceRoleMembers(),
+      "mssql_server_principals":  dataSourceServerPrincipals(),
+      "mssql_user":               dataSourceUser(),
     },
-    DataSourcesMap: map[string]*schema.Resource{},
     ConfigureContextFunc: func(ctx context.Context, data *schema.ResourceData) (interface{}, diag.Diagnostics) {
       return providerConfigure(ctx, data, factory)
     },
   }
+  for name, resource := range provider.ResourcesMap {
+    withContextLogger(resource, name, model.Provider.ResourceLogger)
+    withConnectionWarmup(resource)
+  }
+  for name, resource := range provider.DataSourcesMap {
+    withContextLogger(resource, name, model.Provider.DataSourceLogger)
+  }
+  return provider
+}
+
+// withContextLogger attaches the logger of the resource and function to the context of its CRUD functions,
+// so that the connector can log the duration of each SQL operation with them. Nothing is attached when
+// debug logging is disabled.
+func withContextLogger(resource *schema.Resource, name string, newLogger func(model.Provider, string, string) zerolog.Logger) {
+  name = strings.TrimPrefix(name, "mssql_")
+  wrap := func(f schema.CreateContextFunc, function string) schema.CreateContextFunc {
+    if f == nil {
+      return nil
+    }
+    return func(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+      if provider, ok := meta.(model.Provider); ok {
+        ctx = newLogger(provider, name, function).WithContext(ctx)
+      }
+      return f(ctx, data, meta)
+    }
+  }
+  resource.CreateContext = wrap(resource.CreateContext, "create")
+  resource.ReadContext = schema.ReadContextFunc(wrap(schema.CreateContextFunc(resource.ReadContext), "read"))
+  resource.UpdateContext = schema.UpdateContextFunc(wrap(schema.CreateContextFunc(resource.UpdateContext), "update"))
+  resource.DeleteContext = schema.DeleteContextFunc(wrap(schema.CreateContextFunc(resource.DeleteContext), "delete"))
 }
 
 func providerConfigure(ctx context.Context, data *schema.ResourceData, factory model.ConnectorFactory) (model.Provider, diag.Diagnostics) {
   isDebug := data.Get("debug").(bool)
   logger := newLogger(isDebug)
 
+  var additionalTransientErrors []int32
+  for _, number := range data.Get("additional_transient_error_numbers").([]interface{}) {
+    additionalTransientErrors = append(additionalTransientErrors, int32(number.(int)))
+  }
+
+  var keepAlive *sql.KeepAlive
+  if interval := data.Get("keepalive_interval").(string); interval != "" {
+    d, err := time.ParseDuration(interval)
+    if err != nil {
+      return nil, diag.FromErr(err)
+    }
+    // The stop context is done when Terraform stops the provider. Otherwise the keepalive ends with the process.
+    stopCtx, ok := schema.StopContext(ctx)
+    if !ok {
+      stopCtx = context.Background()
+    }
+    keepAlive = sql.NewKeepAlive(stopCtx, d)
+  }
+
+  var executeAsType, executeAsName string
+  if executeAs := data.Get("execute_as").([]interface{}); len(executeAs) > 0 && executeAs[0] != nil {
+    executeAsType = executeAs[0].(map[string]interface{})["type"].(string)
+    executeAsName = executeAs[0].(map[string]interface{})["name"].(string)
+  }
+
+  var warmup *connectionWarmup
+  if data.Get("connection_warmup").(bool) {
+    warmup = newConnectionWarmup()
+  }
+
   logger.Info().Msg("Created provider")
 
-  return mssqlProvider{factory: factory, logger: logger}, nil
+  return mssqlProvider{
+    factory:                   factory,
+    logger:                    logger,
+    databases:                 newDatabaseCache(),
+    additionalTransientErrors: additionalTransientErrors,
+    ignoreMissingObjects:      data.Get("ignore_missing_objects").(bool),
+    sessionLanguage:           data.Get("session_language").(string),
+    sessionDateFormat:         data.Get("dateformat").(string),
+    executeAsType:             executeAsType,
+    executeAsName:             executeAsName,
+    defaultSchema:             data.Get("default_schema").(string),
+    keepAlive:                 keepAlive,
+    warmup:                    warmup,
+  }, nil
+}
+
+func validateKeepAliveInterval(i interface{}, k string) ([]string, []error) {
+  d, err := time.ParseDuration(i.(string))
+  if err != nil {
+    return nil, []error{fmt.Errorf("expected %s to be a duration, e.g. 5m, got %s", k, i)}
+  }
+  if d < time.Minute {
+    return nil, []error{fmt.Errorf("expected %s to be at least 1m, got %s", k, i)}
+  }
+  return nil, nil
 }
 
 func (p mssqlProvider) GetConnector(prefix string, data *schema.ResourceData) (interface{}, error) {
-  return p.factory.GetConnector(prefix, data)
+  connector, err := p.factory.GetConnector(prefix, data)
+  if c, ok := connector.(*sql.Connector); ok {
+    c.AdditionalTransientErrors = p.additionalTransientErrors
+    c.SessionLanguage = p.sessionLanguage
+    c.SessionDateFormat = p.sessionDateFormat
+    c.ExecuteAsType = p.executeAsType
+    c.ExecuteAsName = p.executeAsName
+    c.KeepAlive = p.keepAlive
+  }
+  return connector, err
 }
 
 func (p mssqlProvider) ResourceLogger(resource, function string) zerolog.Logger {
@@ -75,6 +280,14 @@ func (p mssqlProvider) DataSourceLogger(datasource, function string) zerolog.Log
   return p.logger.With().Str("datasource", datasource).Str("func", function).Logger()
 }
 
+func (p mssqlProvider) IgnoreMissingObjects() bool {
+  return p.ignoreMissingObjects
+}
+
+func (p mssqlProvider) DefaultSchema() string {
+  return p.defaultSchema
+}
+
 func newLogger(isDebug bool) *zerolog.Logger {
   var writer io.Writer = nil
   logLevel := zerolog.Disabled
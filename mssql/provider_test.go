@@ -5,6 +5,7 @@ import (
   "context"
   sql2 "database/sql"
   "fmt"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
   "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
   "os"
@@ -53,6 +54,35 @@ func testAccPreCheck(t *testing.T) {
   }
 }
 
+// testAccLocalDatabase creates a database with a unique name on the local SQL Server, and drops it when
+// the test completes. Tests using separate databases can safely run in parallel. Nothing is created
+// unless local acceptance tests are enabled.
+func testAccLocalDatabase(t *testing.T, prefix string) string {
+  name := prefix + "_" + acctest.RandString(8)
+  if !runLocalAccTests {
+    return name
+  }
+  connector := &sql.Connector{
+    Host:    "localhost",
+    Port:    DefaultPort,
+    Timeout: 60 * time.Second,
+    Login: &sql.LoginUser{
+      Username: os.Getenv("MSSQL_USERNAME"),
+      Password: os.Getenv("MSSQL_PASSWORD"),
+    },
+  }
+  if err := connector.ExecContext(context.Background(), "CREATE DATABASE "+name); err != nil {
+    t.Fatalf("unable to create database %s: %s", name, err)
+  }
+  t.Cleanup(func() {
+    cmd := "ALTER DATABASE " + name + " SET SINGLE_USER WITH ROLLBACK IMMEDIATE; DROP DATABASE " + name
+    if err := connector.ExecContext(context.Background(), cmd); err != nil {
+      t.Errorf("unable to drop database %s: %s", name, err)
+    }
+  })
+  return name
+}
+
 type Check struct {
   name, op string
   expected interface{}
@@ -0,0 +1,184 @@
+package mssql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// ansiOptionProps are the arguments of mssql_database_ansi_options. Each is the lower case name of the
+// option in ALTER DATABASE ... SET.
+var ansiOptionProps = []string{
+	"ansi_nulls",
+	"ansi_padding",
+	"ansi_warnings",
+	"arithabort",
+	"concat_null_yields_null",
+	"quoted_identifier",
+	"numeric_roundabort",
+}
+
+type AnsiOptionsConnector interface {
+	GetDatabaseAnsiOptions(ctx context.Context, database string) (map[string]bool, error)
+	SetDatabaseAnsiOptions(ctx context.Context, database string, options map[string]bool) error
+}
+
+func resourceDatabaseAnsiOptions() *schema.Resource {
+	s := map[string]*schema.Schema{
+		serverProp: {
+			Type:     schema.TypeList,
+			MaxItems: 1,
+			Required: true,
+			Elem: &schema.Resource{
+				Schema: getServerSchema(serverProp),
+			},
+		},
+		databaseProp: {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+	}
+	for _, prop := range ansiOptionProps {
+		s[prop] = &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		}
+	}
+	return &schema.Resource{
+		CreateContext: resourceDatabaseAnsiOptionsCreate,
+		ReadContext:   resourceDatabaseAnsiOptionsRead,
+		UpdateContext: resourceDatabaseAnsiOptionsUpdate,
+		DeleteContext: resourceDatabaseAnsiOptionsDelete,
+		Schema:        s,
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseAnsiOptionsCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_ansi_options", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseFeatureID(data, "ansi_options"))
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setAnsiOptions(ctx, meta, data, ansiOptionProps); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getDatabaseFeatureID(data, "ansi_options"))
+
+	logger.Info().Msgf("set ANSI options on database [%s]", database)
+
+	return resourceDatabaseAnsiOptionsRead(ctx, data, meta)
+}
+
+func resourceDatabaseAnsiOptionsRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_ansi_options", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getAnsiOptionsConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	options, err := connector.GetDatabaseAnsiOptions(ctx, database)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read ANSI options of database [%s]", database))
+	}
+	if options == nil {
+		logger.Info().Msgf("No database [%s] found", database)
+		data.SetId("")
+		return nil
+	}
+
+	for _, prop := range ansiOptionProps {
+		if err = data.Set(prop, options[strings.ToUpper(prop)]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceDatabaseAnsiOptionsUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_ansi_options", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	var changed []string
+	for _, prop := range ansiOptionProps {
+		if data.HasChange(prop) {
+			changed = append(changed, prop)
+		}
+	}
+	if len(changed) > 0 {
+		if err := setAnsiOptions(ctx, meta, data, changed); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated ANSI options on database [%s]", database)
+	}
+
+	return resourceDatabaseAnsiOptionsRead(ctx, data, meta)
+}
+
+func resourceDatabaseAnsiOptionsDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_ansi_options", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	// The options are left as they are, as there is no record of the values they should revert to.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// setAnsiOptions sets those of props that are present in the configuration. Options that are not
+// configured are left as they are.
+func setAnsiOptions(ctx context.Context, meta interface{}, data *schema.ResourceData, props []string) error {
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getAnsiOptionsConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	config := data.GetRawConfig()
+	options := make(map[string]bool)
+	for _, prop := range props {
+		if value := config.GetAttr(prop); !value.IsNull() && value.IsKnown() {
+			options[strings.ToUpper(prop)] = value.True()
+		}
+	}
+	if len(options) == 0 {
+		return nil
+	}
+
+	if err = connector.SetDatabaseAnsiOptions(ctx, database, options); err != nil {
+		return errors.Wrapf(err, "unable to set ANSI options on database [%s]", database)
+	}
+	return nil
+}
+
+func getAnsiOptionsConnector(meta interface{}, data *schema.ResourceData) (AnsiOptionsConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(AnsiOptionsConnector), nil
+}
@@ -0,0 +1,52 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseAnsiOptions_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "ansi_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseAnsiOptions(t, "test", map[string]interface{}{"database": database, "options": "ansi_nulls = true\nquoted_identifier = true"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_ansi_options.test", "ansi_nulls", "true"),
+					resource.TestCheckResourceAttr("mssql_database_ansi_options.test", "quoted_identifier", "true"),
+					// Options that are not set are read, but left as they are
+					resource.TestCheckResourceAttr("mssql_database_ansi_options.test", "numeric_roundabort", "false"),
+				),
+			},
+			{
+				Config: testAccCheckDatabaseAnsiOptions(t, "test", map[string]interface{}{"database": database, "options": "ansi_nulls = false\narithabort = true"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_ansi_options.test", "ansi_nulls", "false"),
+					resource.TestCheckResourceAttr("mssql_database_ansi_options.test", "arithabort", "true"),
+					resource.TestCheckResourceAttr("mssql_database_ansi_options.test", "quoted_identifier", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseAnsiOptions(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_ansi_options" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             {{ .options }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
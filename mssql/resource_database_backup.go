@@ -0,0 +1,203 @@
+package mssql
+
+import (
+	"context"
+	"time"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const urlProp = "url"
+const diskProp = "disk"
+const credentialProp = "credential"
+const copyOnlyProp = "copy_only"
+const finishDateProp = "finish_date"
+const lastLsnProp = "last_lsn"
+
+var operationTimeout = schema.DefaultTimeout(60 * time.Minute)
+
+type BackupConnector interface {
+	BackupDatabase(ctx context.Context, backup *model.Backup) error
+	GetBackup(ctx context.Context, backup *model.Backup) error
+	RestoreDatabase(ctx context.Context, restore *model.Restore) error
+	GetRestore(ctx context.Context, restore *model.Restore) error
+	GetOperationProgress(ctx context.Context, command, database string) (float64, bool, error)
+}
+
+func resourceDatabaseBackup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseBackupCreate,
+		ReadContext:   resourceDatabaseBackupRead,
+		UpdateContext: resourceDatabaseBackupUpdate,
+		DeleteContext: resourceDatabaseBackupDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			urlProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{urlProp, diskProp},
+			},
+			diskProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{urlProp, diskProp},
+			},
+			credentialProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			copyOnlyProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			triggersProp: {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			finishDateProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			lastLsnProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create:  operationTimeout,
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseBackupCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_backup", "create")
+
+	backup := &model.Backup{
+		Database:   data.Get(databaseProp).(string),
+		Url:        data.Get(urlProp).(string),
+		Disk:       data.Get(diskProp).(string),
+		Credential: data.Get(credentialProp).(string),
+		CopyOnly:   data.Get(copyOnlyProp).(bool),
+	}
+
+	connector, err := getBackupConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stop, err := logOperationProgress(ctx, logger, meta, data, "BACKUP DATABASE", backup.Database)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = connector.BackupDatabase(ctx, backup)
+	stop()
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to back up database [%s]", backup.Database))
+	}
+
+	data.SetId(getTriggeredID(data, "backup"))
+
+	logger.Info().Msgf("backed up database [%s]", backup.Database)
+
+	if err = connector.GetBackup(ctx, backup); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read backup history of database [%s]", backup.Database))
+	}
+	if err = data.Set(finishDateProp, backup.FinishDate); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(lastLsnProp, backup.LastLSN); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDatabaseBackupRead(ctx, data, meta)
+}
+
+func resourceDatabaseBackupRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_backup", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	// A backup is a completed operation, so there is nothing to refresh.
+
+	return nil
+}
+
+func resourceDatabaseBackupUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_backup", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	// Only the server login details can change in place, which does not repeat the backup.
+
+	return resourceDatabaseBackupRead(ctx, data, meta)
+}
+
+func resourceDatabaseBackupDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_backup", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	// The backup itself is left in place.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// logOperationProgress logs the progress of a long-running command until the returned function is called.
+func logOperationProgress(ctx context.Context, logger zerolog.Logger, meta interface{}, data *schema.ResourceData, command, database string) (func(), error) {
+	// Poll on a separate connector, as the operation's connector is busy.
+	connector, err := getBackupConnector(meta, data)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if progress, ok, err := connector.GetOperationProgress(ctx, command, database); err == nil && ok {
+					logger.Info().Msgf("%s [%s] %.0f%% complete", command, database, progress)
+				}
+			}
+		}
+	}()
+	return cancel, nil
+}
+
+func getBackupConnector(meta interface{}, data *schema.ResourceData) (BackupConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(BackupConnector), nil
+}
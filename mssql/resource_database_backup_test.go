@@ -0,0 +1,67 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseBackup_Local_BackupAndRestore(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseBackup(t, "test", map[string]interface{}{"database": "backup_test", "disk": "/var/opt/mssql/data/backup_test.bak"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_backup.test", "database", "backup_test"),
+					resource.TestCheckResourceAttr("mssql_database_backup.test", "copy_only", "true"),
+					resource.TestCheckResourceAttrSet("mssql_database_backup.test", "finish_date"),
+					resource.TestCheckResourceAttrSet("mssql_database_backup.test", "last_lsn"),
+					resource.TestCheckResourceAttr("mssql_database_restore.test", "database", "backup_test"),
+					resource.TestCheckResourceAttrSet("mssql_database_restore.test", "restore_date"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseBackup(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_raw_exec" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             create_sql = "CREATE DATABASE [{{ .database }}]"
+             delete_sql = "DROP DATABASE [{{ .database }}]"
+           }
+           resource "mssql_database_backup" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database  = "{{ .database }}"
+             disk      = "{{ .disk }}"
+             copy_only = true
+             depends_on = [mssql_raw_exec.{{ .name }}]
+           }
+           resource "mssql_database_restore" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             disk     = "{{ .disk }}"
+             replace  = true
+             triggers = {
+               backup = mssql_database_backup.{{ .name }}.id
+             }
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
@@ -0,0 +1,117 @@
+package mssql
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+func resourceDatabaseCDC() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseCDCCreate,
+		ReadContext:   resourceDatabaseCDCRead,
+		UpdateContext: resourceDatabaseCDCUpdate,
+		DeleteContext: resourceDatabaseCDCDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseCDCCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_cdc", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseFeatureID(data, "cdc"))
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	connector, err := getChangeTrackingConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.EnableCDC(ctx, database); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to enable change data capture on database [%s]", database))
+	}
+
+	data.SetId(getDatabaseFeatureID(data, "cdc"))
+
+	logger.Info().Msgf("enabled change data capture on database [%s]", database)
+
+	return resourceDatabaseCDCRead(ctx, data, meta)
+}
+
+func resourceDatabaseCDCRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_cdc", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getChangeTrackingConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	enabled, err := connector.GetCDCEnabled(ctx, database)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read change data capture of database [%s]", database))
+	}
+	if !enabled {
+		logger.Info().Msgf("Change data capture not enabled on database [%s]", database)
+		data.SetId("")
+	}
+
+	return nil
+}
+
+func resourceDatabaseCDCUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_cdc", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	// Only the server login details can change in place.
+
+	return resourceDatabaseCDCRead(ctx, data, meta)
+}
+
+func resourceDatabaseCDCDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_cdc", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getChangeTrackingConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.DisableCDC(ctx, database); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to disable change data capture on database [%s]", database))
+	}
+
+	logger.Info().Msgf("disabled change data capture on database [%s]", database)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseCDC_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "cdc_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseCDC(t, "test", map[string]interface{}{"database": database}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_cdc.test", "database", database),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseCDC(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_cdc" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
@@ -0,0 +1,198 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const retentionPeriodProp = "retention_period"
+const retentionPeriodUnitsProp = "retention_period_units"
+const autoCleanupProp = "auto_cleanup"
+
+type ChangeTrackingConnector interface {
+	GetChangeTracking(ctx context.Context, database string) (*model.ChangeTracking, error)
+	SetChangeTracking(ctx context.Context, tracking *model.ChangeTracking) error
+	DisableChangeTracking(ctx context.Context, database string) error
+	GetCDCEnabled(ctx context.Context, database string) (bool, error)
+	EnableCDC(ctx context.Context, database string) error
+	DisableCDC(ctx context.Context, database string) error
+}
+
+func resourceDatabaseChangeTracking() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseChangeTrackingCreate,
+		ReadContext:   resourceDatabaseChangeTrackingRead,
+		UpdateContext: resourceDatabaseChangeTrackingUpdate,
+		DeleteContext: resourceDatabaseChangeTrackingDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			retentionPeriodProp: {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			retentionPeriodUnitsProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "DAYS",
+				ValidateFunc: validation.StringInSlice([]string{"MINUTES", "HOURS", "DAYS"}, false),
+			},
+			autoCleanupProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseChangeTrackingCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_change_tracking", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseFeatureID(data, "change_tracking"))
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setChangeTracking(ctx, meta, data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getDatabaseFeatureID(data, "change_tracking"))
+
+	logger.Info().Msgf("enabled change tracking on database [%s]", database)
+
+	return resourceDatabaseChangeTrackingRead(ctx, data, meta)
+}
+
+func resourceDatabaseChangeTrackingRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_change_tracking", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getChangeTrackingConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tracking, err := connector.GetChangeTracking(ctx, database)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read change tracking of database [%s]", database))
+	}
+	if tracking == nil {
+		logger.Info().Msgf("No change tracking found for database [%s]", database)
+		data.SetId("")
+	} else {
+		if err = data.Set(retentionPeriodProp, tracking.RetentionPeriod); err != nil {
+			return diag.FromErr(err)
+		}
+		if err = data.Set(retentionPeriodUnitsProp, tracking.RetentionPeriodUnits); err != nil {
+			return diag.FromErr(err)
+		}
+		if err = data.Set(autoCleanupProp, tracking.AutoCleanup); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceDatabaseChangeTrackingUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_change_tracking", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	if data.HasChanges(retentionPeriodProp, retentionPeriodUnitsProp, autoCleanupProp) {
+		if err := setChangeTracking(ctx, meta, data); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated change tracking on database [%s]", database)
+	}
+
+	return resourceDatabaseChangeTrackingRead(ctx, data, meta)
+}
+
+func resourceDatabaseChangeTrackingDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_change_tracking", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getChangeTrackingConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.DisableChangeTracking(ctx, database); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to disable change tracking on database [%s]", database))
+	}
+
+	logger.Info().Msgf("disabled change tracking on database [%s]", database)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+func setChangeTracking(ctx context.Context, meta interface{}, data *schema.ResourceData) error {
+	tracking := &model.ChangeTracking{
+		Database:             data.Get(databaseProp).(string),
+		RetentionPeriod:      data.Get(retentionPeriodProp).(int),
+		RetentionPeriodUnits: data.Get(retentionPeriodUnitsProp).(string),
+		AutoCleanup:          data.Get(autoCleanupProp).(bool),
+	}
+
+	connector, err := getChangeTrackingConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	if err = connector.SetChangeTracking(ctx, tracking); err != nil {
+		return errors.Wrapf(err, "unable to set change tracking on database [%s]", tracking.Database)
+	}
+	return nil
+}
+
+// getDatabaseFeatureID returns the ID of resources that enable a feature on a database.
+func getDatabaseFeatureID(data *schema.ResourceData, feature string) string {
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	database := data.Get(databaseProp).(string)
+	return fmt.Sprintf("sqlserver://%s:%s/%s/%s", host, port, database, feature)
+}
+
+func getChangeTrackingConnector(meta interface{}, data *schema.ResourceData) (ChangeTrackingConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(ChangeTrackingConnector), nil
+}
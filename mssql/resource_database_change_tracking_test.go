@@ -0,0 +1,53 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseChangeTracking_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "change_tracking_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseChangeTracking(t, "test", map[string]interface{}{"database": database}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_change_tracking.test", "retention_period", "2"),
+					resource.TestCheckResourceAttr("mssql_database_change_tracking.test", "retention_period_units", "DAYS"),
+					resource.TestCheckResourceAttr("mssql_database_change_tracking.test", "auto_cleanup", "true"),
+				),
+			},
+			{
+				Config: testAccCheckDatabaseChangeTracking(t, "test", map[string]interface{}{"database": database, "retention_period": 12, "retention_period_units": "HOURS", "auto_cleanup": "false"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_change_tracking.test", "retention_period", "12"),
+					resource.TestCheckResourceAttr("mssql_database_change_tracking.test", "retention_period_units", "HOURS"),
+					resource.TestCheckResourceAttr("mssql_database_change_tracking.test", "auto_cleanup", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseChangeTracking(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_change_tracking" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             {{ with .retention_period }}retention_period = {{ . }}{{ end }}
+             {{ with .retention_period_units }}retention_period_units = "{{ . }}"{{ end }}
+             {{ with .auto_cleanup }}auto_cleanup = {{ . }}{{ end }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
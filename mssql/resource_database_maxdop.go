@@ -0,0 +1,173 @@
+package mssql
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const maxDopProp = "max_dop"
+const secondaryMaxDopProp = "secondary_max_dop"
+const secondaryMaxDopPrimary = "PRIMARY"
+
+type MaxDopConnector interface {
+	GetDatabaseMaxDop(ctx context.Context, database string) (int, string, error)
+	SetDatabaseMaxDop(ctx context.Context, database string, maxDop int, secondary string) error
+}
+
+func resourceDatabaseMaxDop() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseMaxDopCreate,
+		ReadContext:   resourceDatabaseMaxDopRead,
+		UpdateContext: resourceDatabaseMaxDopUpdate,
+		DeleteContext: resourceDatabaseMaxDopDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			maxDopProp: {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(0, 32767),
+			},
+			secondaryMaxDopProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      secondaryMaxDopPrimary,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^(PRIMARY|[0-9]+)$`), "must be PRIMARY or a number"),
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseMaxDopCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_maxdop", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseFeatureID(data, "maxdop"))
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setDatabaseMaxDop(ctx, meta, data, data.Get(maxDopProp).(int), data.Get(secondaryMaxDopProp).(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getDatabaseFeatureID(data, "maxdop"))
+
+	logger.Info().Msgf("set MAXDOP of database [%s]", database)
+
+	return resourceDatabaseMaxDopRead(ctx, data, meta)
+}
+
+func resourceDatabaseMaxDopRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_maxdop", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	exists, err := meta.(model.Provider).DatabaseExists(ctx, serverProp, data, database)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !exists {
+		logger.Info().Msgf("No database [%s] found", database)
+		data.SetId("")
+		return nil
+	}
+
+	connector, err := getMaxDopConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	maxDop, secondary, err := connector.GetDatabaseMaxDop(ctx, database)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read MAXDOP of database [%s]", database))
+	}
+	if err = data.Set(maxDopProp, maxDop); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(secondaryMaxDopProp, secondary); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceDatabaseMaxDopUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_maxdop", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	if data.HasChanges(maxDopProp, secondaryMaxDopProp) {
+		if err := setDatabaseMaxDop(ctx, meta, data, data.Get(maxDopProp).(int), data.Get(secondaryMaxDopProp).(string)); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated MAXDOP of database [%s]", database)
+	}
+
+	return resourceDatabaseMaxDopRead(ctx, data, meta)
+}
+
+func resourceDatabaseMaxDopDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_maxdop", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	// Restore the defaults of new databases.
+	if err := setDatabaseMaxDop(ctx, meta, data, 0, secondaryMaxDopPrimary); err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Info().Msgf("reset MAXDOP of database [%s]", database)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+func setDatabaseMaxDop(ctx context.Context, meta interface{}, data *schema.ResourceData, maxDop int, secondary string) error {
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getMaxDopConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	if err = connector.SetDatabaseMaxDop(ctx, database, maxDop, secondary); err != nil {
+		return errors.Wrapf(err, "unable to set MAXDOP of database [%s]", database)
+	}
+	return nil
+}
+
+func getMaxDopConnector(meta interface{}, data *schema.ResourceData) (MaxDopConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(MaxDopConnector), nil
+}
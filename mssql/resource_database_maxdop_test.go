@@ -0,0 +1,50 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseMaxDop_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "maxdop_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseMaxDop(t, "test", map[string]interface{}{"database": database, "max_dop": 4}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_maxdop.test", "max_dop", "4"),
+					resource.TestCheckResourceAttr("mssql_database_maxdop.test", "secondary_max_dop", "PRIMARY"),
+				),
+			},
+			{
+				Config: testAccCheckDatabaseMaxDop(t, "test", map[string]interface{}{"database": database, "max_dop": 2, "secondary_max_dop": "1"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_maxdop.test", "max_dop", "2"),
+					resource.TestCheckResourceAttr("mssql_database_maxdop.test", "secondary_max_dop", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseMaxDop(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_maxdop" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             max_dop  = {{ .max_dop }}
+             {{ with .secondary_max_dop }}secondary_max_dop = "{{ . }}"{{ end }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
@@ -0,0 +1,269 @@
+package mssql
+
+import (
+	"context"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const autoCloseProp = "auto_close"
+const pageVerifyProp = "page_verify"
+const dataRetentionProp = "data_retention"
+const defaultFilegroupProp = "default_filegroup"
+const filestreamNonTransactedAccessProp = "filestream_non_transacted_access"
+const filestreamDirectoryNameProp = "filestream_directory_name"
+
+// databaseSetOptionProps are the arguments of mssql_database_options that are set with ALTER DATABASE ... SET,
+// with the name of their option.
+var databaseSetOptionProps = map[string]string{
+	autoCloseProp:     "AUTO_CLOSE",
+	pageVerifyProp:    "PAGE_VERIFY",
+	dataRetentionProp: "DATA_RETENTION",
+}
+
+// databaseOptionsProps are all arguments of mssql_database_options, including those that are not set with
+// ALTER DATABASE ... SET <option>, i.e. the default filegroup and the FILESTREAM options.
+var databaseOptionsProps = []string{
+	autoCloseProp,
+	pageVerifyProp,
+	dataRetentionProp,
+	defaultFilegroupProp,
+	filestreamNonTransactedAccessProp,
+	filestreamDirectoryNameProp,
+}
+
+type DatabaseOptionsConnector interface {
+	GetDatabaseOptions(ctx context.Context, database string) (*model.DatabaseOptions, error)
+	SetDatabaseOptions(ctx context.Context, database string, options map[string]string) error
+	SetDatabaseDefaultFilegroup(ctx context.Context, database, filegroup string) error
+	SetDatabaseFilestream(ctx context.Context, database, access, directory string) error
+}
+
+func resourceDatabaseOptions() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseOptionsCreate,
+		ReadContext:   resourceDatabaseOptionsRead,
+		UpdateContext: resourceDatabaseOptionsUpdate,
+		DeleteContext: resourceDatabaseOptionsDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			autoCloseProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			pageVerifyProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"CHECKSUM", "TORN_PAGE_DETECTION", "NONE"}, false),
+			},
+			dataRetentionProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+			},
+			defaultFilegroupProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			filestreamNonTransactedAccessProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"OFF", "READ_ONLY", "FULL"}, false),
+			},
+			filestreamDirectoryNameProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringLenBetween(1, 255),
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseOptionsCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_options", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseFeatureID(data, "options"))
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setDatabaseOptions(ctx, meta, data, databaseOptionsProps); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getDatabaseFeatureID(data, "options"))
+
+	logger.Info().Msgf("set options on database [%s]", database)
+
+	return resourceDatabaseOptionsRead(ctx, data, meta)
+}
+
+func resourceDatabaseOptionsRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_options", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getDatabaseOptionsConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	options, err := connector.GetDatabaseOptions(ctx, database)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read options of database [%s]", database))
+	}
+	if options == nil {
+		logger.Info().Msgf("No database [%s] found", database)
+		data.SetId("")
+		return nil
+	}
+
+	if err = data.Set(autoCloseProp, options.AutoClose); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(pageVerifyProp, options.PageVerify); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(dataRetentionProp, options.DataRetention); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(defaultFilegroupProp, options.DefaultFilegroup); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(filestreamNonTransactedAccessProp, options.FilestreamNonTransactedAccess); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(filestreamDirectoryNameProp, options.FilestreamDirectoryName); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceDatabaseOptionsUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_options", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	var changed []string
+	for _, prop := range databaseOptionsProps {
+		if data.HasChange(prop) {
+			changed = append(changed, prop)
+		}
+	}
+	if len(changed) > 0 {
+		if err := setDatabaseOptions(ctx, meta, data, changed); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated options on database [%s]", database)
+	}
+
+	return resourceDatabaseOptionsRead(ctx, data, meta)
+}
+
+func resourceDatabaseOptionsDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_options", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	// The options are left as they are, as there is no record of the values they should revert to.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// setDatabaseOptions sets those of props that are present in the configuration. Options that are not
+// configured are left as they are.
+func setDatabaseOptions(ctx context.Context, meta interface{}, data *schema.ResourceData, props []string) error {
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getDatabaseOptionsConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	config := data.GetRawConfig()
+	options := make(map[string]string)
+	var defaultFilegroup, filestreamAccess, filestreamDirectory string
+	for _, prop := range props {
+		value := config.GetAttr(prop)
+		if value.IsNull() || !value.IsKnown() {
+			continue
+		}
+		switch prop {
+		case defaultFilegroupProp:
+			defaultFilegroup = value.AsString()
+		case filestreamNonTransactedAccessProp:
+			filestreamAccess = value.AsString()
+		case filestreamDirectoryNameProp:
+			filestreamDirectory = value.AsString()
+		case pageVerifyProp:
+			options[databaseSetOptionProps[prop]] = value.AsString()
+		default:
+			options[databaseSetOptionProps[prop]] = onOff(value.True())
+		}
+	}
+
+	if len(options) > 0 {
+		if err = connector.SetDatabaseOptions(ctx, database, options); err != nil {
+			return errors.Wrapf(err, "unable to set options on database [%s]", database)
+		}
+	}
+	if filestreamAccess != "" || filestreamDirectory != "" {
+		if err = connector.SetDatabaseFilestream(ctx, database, filestreamAccess, filestreamDirectory); err != nil {
+			return errors.Wrapf(err, "unable to set FILESTREAM options on database [%s]", database)
+		}
+	}
+	if defaultFilegroup != "" {
+		if err = connector.SetDatabaseDefaultFilegroup(ctx, database, defaultFilegroup); err != nil {
+			return errors.Wrapf(err, "unable to set default filegroup of database [%s] to [%s]", database, defaultFilegroup)
+		}
+	}
+	return nil
+}
+
+func onOff(on bool) string {
+	if on {
+		return "ON"
+	}
+	return "OFF"
+}
+
+func getDatabaseOptionsConnector(meta interface{}, data *schema.ResourceData) (DatabaseOptionsConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(DatabaseOptionsConnector), nil
+}
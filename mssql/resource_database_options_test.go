@@ -0,0 +1,76 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseOptions_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "options_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseOptions(t, "test", map[string]interface{}{"database": database, "options": "auto_close = true\npage_verify = \"TORN_PAGE_DETECTION\""}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_options.test", "auto_close", "true"),
+					resource.TestCheckResourceAttr("mssql_database_options.test", "page_verify", "TORN_PAGE_DETECTION"),
+					// SQL Server does not support the option, so it is read with its behaviour
+					resource.TestCheckResourceAttr("mssql_database_options.test", "data_retention", "false"),
+					resource.TestCheckResourceAttr("mssql_database_options.test", "default_filegroup", "PRIMARY"),
+					resource.TestCheckResourceAttr("mssql_database_options.test", "filestream_non_transacted_access", "OFF"),
+				),
+			},
+			{
+				Config: testAccCheckDatabaseOptions(t, "test", map[string]interface{}{"database": database, "options": "auto_close = false\npage_verify = \"CHECKSUM\""}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_options.test", "auto_close", "false"),
+					resource.TestCheckResourceAttr("mssql_database_options.test", "page_verify", "CHECKSUM"),
+				),
+			},
+			{
+				// Options that are not set are read, but left as they are
+				Config: testAccCheckDatabaseOptions(t, "test", map[string]interface{}{"database": database, "options": "auto_close = false"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_options.test", "page_verify", "CHECKSUM"),
+				),
+			},
+			{
+				// Setting the filegroup that is already the default does nothing
+				Config: testAccCheckDatabaseOptions(t, "test", map[string]interface{}{"database": database, "options": "default_filegroup = \"PRIMARY\""}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_options.test", "default_filegroup", "PRIMARY"),
+				),
+			},
+			{
+				Config:      testAccCheckDatabaseOptions(t, "test", map[string]interface{}{"database": database, "options": "default_filegroup = \"MISSING\""}),
+				ExpectError: regexp.MustCompile("filegroup \\[MISSING\\] does not exist"),
+			},
+			{
+				Config:      testAccCheckDatabaseOptions(t, "test", map[string]interface{}{"database": database, "options": "data_retention = true"}),
+				ExpectError: regexp.MustCompile("DATA_RETENTION is not supported on this edition of SQL Server, only on Azure SQL Edge"),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseOptions(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_options" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             {{ .options }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
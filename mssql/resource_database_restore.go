@@ -0,0 +1,146 @@
+package mssql
+
+import (
+	"context"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const replaceProp = "replace"
+const restoreDateProp = "restore_date"
+
+func resourceDatabaseRestore() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseRestoreCreate,
+		ReadContext:   resourceDatabaseRestoreRead,
+		UpdateContext: resourceDatabaseRestoreUpdate,
+		DeleteContext: resourceDatabaseRestoreDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			urlProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{urlProp, diskProp},
+			},
+			diskProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{urlProp, diskProp},
+			},
+			credentialProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			replaceProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+			triggersProp: {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			restoreDateProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create:  operationTimeout,
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseRestoreCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_restore", "create")
+
+	restore := &model.Restore{
+		Database:   data.Get(databaseProp).(string),
+		Url:        data.Get(urlProp).(string),
+		Disk:       data.Get(diskProp).(string),
+		Credential: data.Get(credentialProp).(string),
+		Replace:    data.Get(replaceProp).(bool),
+	}
+
+	connector, err := getBackupConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stop, err := logOperationProgress(ctx, logger, meta, data, "RESTORE DATABASE", restore.Database)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = connector.RestoreDatabase(ctx, restore)
+	stop()
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to restore database [%s]", restore.Database))
+	}
+
+	data.SetId(getTriggeredID(data, "restore"))
+
+	logger.Info().Msgf("restored database [%s]", restore.Database)
+
+	if err = connector.GetRestore(ctx, restore); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read restore history of database [%s]", restore.Database))
+	}
+	if err = data.Set(restoreDateProp, restore.RestoreDate); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDatabaseRestoreRead(ctx, data, meta)
+}
+
+func resourceDatabaseRestoreRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_restore", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	// A restore is a completed operation, so there is nothing to refresh.
+
+	return nil
+}
+
+func resourceDatabaseRestoreUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_restore", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	// Only the server login details can change in place, which does not repeat the restore.
+
+	return resourceDatabaseRestoreRead(ctx, data, meta)
+}
+
+func resourceDatabaseRestoreDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_restore", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	// The restored database is left in place.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
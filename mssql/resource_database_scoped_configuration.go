@@ -0,0 +1,214 @@
+package mssql
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const valueProp = "value"
+
+// scopedConfigurationOptions are the named database scoped configurations supported by
+// mssql_database_scoped_configuration, with their accepted values. The first value is the default of new
+// databases, which is restored when the resource is destroyed. MAXDOP is managed by mssql_database_maxdop.
+var scopedConfigurationOptions = map[string][]string{
+	"ELEVATE_ONLINE":                   {"OFF", "WHEN_SUPPORTED", "FAIL_UNSUPPORTED"},
+	"ELEVATE_RESUMABLE":                {"OFF", "WHEN_SUPPORTED", "FAIL_UNSUPPORTED"},
+	"GLOBAL_TEMPORARY_TABLE_AUTO_DROP": {"ON", "OFF"},
+	"IDENTITY_CACHE":                   {"ON", "OFF"},
+	"LEGACY_CARDINALITY_ESTIMATION":    {"OFF", "ON"},
+	"OPTIMIZE_FOR_AD_HOC_WORKLOADS":    {"OFF", "ON"},
+	"PARAMETER_SNIFFING":               {"ON", "OFF"},
+	"QUERY_OPTIMIZER_HOTFIXES":         {"OFF", "ON"},
+}
+
+type ScopedConfigurationConnector interface {
+	GetDatabaseScopedConfiguration(ctx context.Context, database, name string) (string, error)
+	SetDatabaseScopedConfiguration(ctx context.Context, database, name, value string) error
+}
+
+func resourceDatabaseScopedConfiguration() *schema.Resource {
+	var names []string
+	for name := range scopedConfigurationOptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &schema.Resource{
+		CreateContext: resourceDatabaseScopedConfigurationCreate,
+		ReadContext:   resourceDatabaseScopedConfigurationRead,
+		UpdateContext: resourceDatabaseScopedConfigurationUpdate,
+		DeleteContext: resourceDatabaseScopedConfigurationDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			nameProp: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(names, false),
+			},
+			valueProp: {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+			name := diff.Get(nameProp).(string)
+			values, ok := scopedConfigurationOptions[name]
+			if !ok || !diff.NewValueKnown(valueProp) {
+				return nil
+			}
+			value := diff.Get(valueProp).(string)
+			for _, v := range values {
+				if v == value {
+					return nil
+				}
+			}
+			return errors.Errorf("invalid value [%s] of %s, expected one of %s", value, name, strings.Join(values, ", "))
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseScopedConfigurationCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_scoped_configuration", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseScopedConfigurationID(data))
+
+	database := data.Get(databaseProp).(string)
+	name := data.Get(nameProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setDatabaseScopedConfiguration(ctx, meta, data, data.Get(valueProp).(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getDatabaseScopedConfigurationID(data))
+
+	logger.Info().Msgf("set %s of database [%s]", name, database)
+
+	return resourceDatabaseScopedConfigurationRead(ctx, data, meta)
+}
+
+func resourceDatabaseScopedConfigurationRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_scoped_configuration", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	name := data.Get(nameProp).(string)
+
+	exists, err := meta.(model.Provider).DatabaseExists(ctx, serverProp, data, database)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !exists {
+		logger.Info().Msgf("No database [%s] found", database)
+		data.SetId("")
+		return nil
+	}
+
+	connector, err := getScopedConfigurationConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	value, err := connector.GetDatabaseScopedConfiguration(ctx, database, name)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read %s of database [%s]", name, database))
+	}
+	if value == "" {
+		return diag.Errorf("%s is not supported by the version or edition of server [%s]", name, serverKey(serverProp, data))
+	}
+	if err = data.Set(valueProp, value); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceDatabaseScopedConfigurationUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_scoped_configuration", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	name := data.Get(nameProp).(string)
+
+	if data.HasChange(valueProp) {
+		if err := setDatabaseScopedConfiguration(ctx, meta, data, data.Get(valueProp).(string)); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated %s of database [%s]", name, database)
+	}
+
+	return resourceDatabaseScopedConfigurationRead(ctx, data, meta)
+}
+
+func resourceDatabaseScopedConfigurationDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_scoped_configuration", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	name := data.Get(nameProp).(string)
+
+	// Restore the default of new databases.
+	if err := setDatabaseScopedConfiguration(ctx, meta, data, scopedConfigurationOptions[name][0]); err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Info().Msgf("reset %s of database [%s]", name, database)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+func setDatabaseScopedConfiguration(ctx context.Context, meta interface{}, data *schema.ResourceData, value string) error {
+	database := data.Get(databaseProp).(string)
+	name := data.Get(nameProp).(string)
+
+	connector, err := getScopedConfigurationConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	if err = connector.SetDatabaseScopedConfiguration(ctx, database, name, value); err != nil {
+		return errors.Wrapf(err, "unable to set %s of database [%s]", name, database)
+	}
+	return nil
+}
+
+func getDatabaseScopedConfigurationID(data *schema.ResourceData) string {
+	return getDatabaseFeatureID(data, "scoped_configuration/"+strings.ToLower(data.Get(nameProp).(string)))
+}
+
+func getScopedConfigurationConnector(meta interface{}, data *schema.ResourceData) (ScopedConfigurationConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(ScopedConfigurationConnector), nil
+}
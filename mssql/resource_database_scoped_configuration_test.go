@@ -0,0 +1,61 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseScopedConfiguration_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "scoped_configuration_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseScopedConfiguration(t, "test", map[string]interface{}{"database": database, "name": "IDENTITY_CACHE", "value": "OFF"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_scoped_configuration.test", "value", "OFF"),
+				),
+			},
+			{
+				Config: testAccCheckDatabaseScopedConfiguration(t, "test", map[string]interface{}{"database": database, "name": "IDENTITY_CACHE", "value": "ON"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_scoped_configuration.test", "value", "ON"),
+				),
+			},
+			{
+				// ELEVATE_ONLINE is stored as text rather than as a number
+				Config: testAccCheckDatabaseScopedConfiguration(t, "test", map[string]interface{}{"database": database, "name": "ELEVATE_ONLINE", "value": "WHEN_SUPPORTED"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_scoped_configuration.test", "value", "WHEN_SUPPORTED"),
+				),
+			},
+			{
+				Config:      testAccCheckDatabaseScopedConfiguration(t, "test", map[string]interface{}{"database": database, "name": "ELEVATE_ONLINE", "value": "ON"}),
+				ExpectError: regexp.MustCompile("invalid value \\[ON\\] of ELEVATE_ONLINE"),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseScopedConfiguration(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_scoped_configuration" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             name     = "{{ .config }}"
+             value    = "{{ .value }}"
+           }`
+	data["config"] = data["name"]
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
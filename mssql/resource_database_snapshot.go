@@ -0,0 +1,172 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const directoryProp = "directory"
+const createDateProp = "create_date"
+
+type SnapshotConnector interface {
+	CreateDatabaseSnapshot(ctx context.Context, snapshot *model.DatabaseSnapshot) error
+	GetDatabaseSnapshot(ctx context.Context, name string) (*model.DatabaseSnapshot, error)
+	DropDatabaseSnapshot(ctx context.Context, name string) error
+	RevertDatabaseSnapshot(ctx context.Context, database, name string) error
+}
+
+func resourceDatabaseSnapshot() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseSnapshotCreate,
+		ReadContext:   resourceDatabaseSnapshotRead,
+		UpdateContext: resourceDatabaseSnapshotUpdate,
+		DeleteContext: resourceDatabaseSnapshotDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			nameProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			directoryProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			createDateProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseSnapshotCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_snapshot", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseSnapshotID(data))
+
+	snapshot := &model.DatabaseSnapshot{
+		Name:      data.Get(nameProp).(string),
+		Database:  data.Get(databaseProp).(string),
+		Directory: strings.TrimRight(data.Get(directoryProp).(string), `/\`),
+	}
+
+	if err := checkDatabaseExists(ctx, meta, data, snapshot.Database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	connector, err := getSnapshotConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.CreateDatabaseSnapshot(ctx, snapshot); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to create snapshot [%s] of database [%s]", snapshot.Name, snapshot.Database))
+	}
+
+	data.SetId(getDatabaseSnapshotID(data))
+
+	logger.Info().Msgf("created snapshot [%s] of database [%s]", snapshot.Name, snapshot.Database)
+
+	return resourceDatabaseSnapshotRead(ctx, data, meta)
+}
+
+func resourceDatabaseSnapshotRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_snapshot", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	name := data.Get(nameProp).(string)
+
+	connector, err := getSnapshotConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	snapshot, err := connector.GetDatabaseSnapshot(ctx, name)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read snapshot [%s]", name))
+	}
+	if snapshot == nil {
+		logger.Info().Msgf("No snapshot found for [%s]", name)
+		data.SetId("")
+	} else {
+		if err = data.Set(databaseProp, snapshot.Database); err != nil {
+			return diag.FromErr(err)
+		}
+		if err = data.Set(createDateProp, snapshot.CreateDate); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceDatabaseSnapshotUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_snapshot", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	// Only the server login details can change in place.
+
+	return resourceDatabaseSnapshotRead(ctx, data, meta)
+}
+
+func resourceDatabaseSnapshotDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_snapshot", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	name := data.Get(nameProp).(string)
+
+	connector, err := getSnapshotConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.DropDatabaseSnapshot(ctx, name); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to drop snapshot [%s]", name))
+	}
+
+	logger.Info().Msgf("dropped snapshot [%s]", name)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+func getDatabaseSnapshotID(data *schema.ResourceData) string {
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	database := data.Get(databaseProp).(string)
+	name := data.Get(nameProp).(string)
+	return fmt.Sprintf("sqlserver://%s:%s/%s/snapshot/%s", host, port, database, name)
+}
+
+func getSnapshotConnector(meta interface{}, data *schema.ResourceData) (SnapshotConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(SnapshotConnector), nil
+}
@@ -0,0 +1,104 @@
+package mssql
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const snapshotProp = "snapshot"
+
+func resourceDatabaseSnapshotRevert() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseSnapshotRevertCreate,
+		ReadContext:   resourceDatabaseSnapshotRevertRead,
+		UpdateContext: resourceDatabaseSnapshotRevertUpdate,
+		DeleteContext: resourceDatabaseSnapshotRevertDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			snapshotProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			triggersProp: {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create:  operationTimeout,
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseSnapshotRevertCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_snapshot_revert", "create")
+
+	database := data.Get(databaseProp).(string)
+	snapshot := data.Get(snapshotProp).(string)
+
+	connector, err := getSnapshotConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.RevertDatabaseSnapshot(ctx, database, snapshot); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to revert database [%s] to snapshot [%s]", database, snapshot))
+	}
+
+	data.SetId(getTriggeredID(data, "revert"))
+
+	logger.Info().Msgf("reverted database [%s] to snapshot [%s]", database, snapshot)
+
+	return resourceDatabaseSnapshotRevertRead(ctx, data, meta)
+}
+
+func resourceDatabaseSnapshotRevertRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_snapshot_revert", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	// A revert is a completed operation, so there is nothing to refresh.
+
+	return nil
+}
+
+func resourceDatabaseSnapshotRevertUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_snapshot_revert", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	// Only the server login details can change in place, which does not repeat the revert.
+
+	return resourceDatabaseSnapshotRevertRead(ctx, data, meta)
+}
+
+func resourceDatabaseSnapshotRevertDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_snapshot_revert", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	// The reverted database is left as it is.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseSnapshot_Local_SnapshotAndRevert(t *testing.T) {
+	database := testAccLocalDatabase(t, "snapshot_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseSnapshot(t, "test", map[string]interface{}{"database": database, "snapshot": database + "_snap"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_snapshot.test", "database", database),
+					resource.TestCheckResourceAttr("mssql_database_snapshot.test", "name", database+"_snap"),
+					resource.TestCheckResourceAttrSet("mssql_database_snapshot.test", "create_date"),
+					resource.TestCheckResourceAttr("mssql_database_snapshot_revert.test", "snapshot", database+"_snap"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseSnapshot(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_snapshot" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             name     = "{{ .snapshot }}"
+           }
+           resource "mssql_database_snapshot_revert" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             snapshot = mssql_database_snapshot.{{ .name }}.name
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
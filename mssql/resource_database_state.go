@@ -0,0 +1,185 @@
+package mssql
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const stateProp = "state"
+const transitionRollbackProp = "transition_rollback"
+
+const (
+	databaseStateReadWrite = "READ_WRITE"
+	databaseStateReadOnly  = "READ_ONLY"
+	databaseStateOffline   = "OFFLINE"
+)
+
+type DatabaseStateConnector interface {
+	GetDatabaseState(ctx context.Context, database string) (string, bool, error)
+	SetDatabaseState(ctx context.Context, database, state, rollback string) error
+}
+
+func resourceDatabaseState() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseStateCreate,
+		ReadContext:   resourceDatabaseStateRead,
+		UpdateContext: resourceDatabaseStateUpdate,
+		DeleteContext: resourceDatabaseStateDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			stateProp: {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{databaseStateReadWrite, databaseStateReadOnly, databaseStateOffline}, false),
+			},
+			transitionRollbackProp: {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^(IMMEDIATE|NO_WAIT|[0-9]+)$`), "must be IMMEDIATE, NO_WAIT or a number of seconds"),
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseStateCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_state", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseFeatureID(data, "state"))
+
+	database := data.Get(databaseProp).(string)
+	state := data.Get(stateProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setDatabaseState(ctx, meta, data, state, data.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getDatabaseFeatureID(data, "state"))
+
+	logger.Info().Msgf("set state of database [%s] to %s", database, state)
+
+	return resourceDatabaseStateRead(ctx, data, meta)
+}
+
+func resourceDatabaseStateRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_state", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getDatabaseStateConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	state, _, err := connector.GetDatabaseState(ctx, database)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read state of database [%s]", database))
+	}
+	if state == "" {
+		logger.Info().Msgf("No database [%s] found", database)
+		data.SetId("")
+	} else {
+		if err = data.Set(stateProp, state); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceDatabaseStateUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_state", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	state := data.Get(stateProp).(string)
+
+	if data.HasChange(stateProp) {
+		if err := setDatabaseState(ctx, meta, data, state, data.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("set state of database [%s] to %s", database, state)
+	}
+
+	return resourceDatabaseStateRead(ctx, data, meta)
+}
+
+func resourceDatabaseStateDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_state", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	if data.Get(stateProp).(string) != databaseStateReadWrite {
+		if err := setDatabaseState(ctx, meta, data, databaseStateReadWrite, data.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("set state of database [%s] to %s", database, databaseStateReadWrite)
+	}
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// setDatabaseState changes the state of the database, and waits for the change to complete.
+func setDatabaseState(ctx context.Context, meta interface{}, data *schema.ResourceData, state string, timeout time.Duration) error {
+	database := data.Get(databaseProp).(string)
+	rollback := data.Get(transitionRollbackProp).(string)
+
+	connector, err := getDatabaseStateConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	if err = connector.SetDatabaseState(ctx, database, state, rollback); err != nil {
+		return errors.Wrapf(err, "unable to set state of database [%s] to %s", database, state)
+	}
+
+	return retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		current, pending, err := connector.GetDatabaseState(ctx, database)
+		if err != nil {
+			return retry.NonRetryableError(errors.Wrapf(err, "unable to read state of database [%s]", database))
+		}
+		if pending || current != state {
+			return retry.RetryableError(errors.Errorf("database [%s] is %s, waiting for %s", database, current, state))
+		}
+		return nil
+	})
+}
+
+func getDatabaseStateConnector(meta interface{}, data *schema.ResourceData) (DatabaseStateConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(DatabaseStateConnector), nil
+}
@@ -0,0 +1,54 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseState_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "state_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseState(t, "test", map[string]interface{}{"database": database, "state": "READ_ONLY", "transition_rollback": "IMMEDIATE"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_state.test", "state", "READ_ONLY"),
+				),
+			},
+			{
+				Config: testAccCheckDatabaseState(t, "test", map[string]interface{}{"database": database, "state": "OFFLINE", "transition_rollback": "5"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_state.test", "state", "OFFLINE"),
+				),
+			},
+			{
+				Config: testAccCheckDatabaseState(t, "test", map[string]interface{}{"database": database, "state": "READ_WRITE"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_state.test", "state", "READ_WRITE"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseState(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_state" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             state    = "{{ .state }}"
+             {{ with .transition_rollback }}transition_rollback = "{{ . }}"{{ end }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
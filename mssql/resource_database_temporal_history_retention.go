@@ -0,0 +1,154 @@
+package mssql
+
+import (
+	"context"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const enabledProp = "enabled"
+
+type TemporalHistoryRetentionConnector interface {
+	GetTemporalHistoryRetention(ctx context.Context, database string) (*bool, error)
+	SetTemporalHistoryRetention(ctx context.Context, database string, enabled bool) error
+}
+
+func resourceDatabaseTemporalHistoryRetention() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseTemporalHistoryRetentionCreate,
+		ReadContext:   resourceDatabaseTemporalHistoryRetentionRead,
+		UpdateContext: resourceDatabaseTemporalHistoryRetentionUpdate,
+		DeleteContext: resourceDatabaseTemporalHistoryRetentionDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			enabledProp: {
+				Type:     schema.TypeBool,
+				Required: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceDatabaseTemporalHistoryRetentionCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_temporal_history_retention", "create")
+	logger.Debug().Msgf("Create %s", getDatabaseFeatureID(data, "temporal_history_retention"))
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setTemporalHistoryRetention(ctx, meta, data, data.Get(enabledProp).(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getDatabaseFeatureID(data, "temporal_history_retention"))
+
+	logger.Info().Msgf("set temporal history retention on database [%s]", database)
+
+	return resourceDatabaseTemporalHistoryRetentionRead(ctx, data, meta)
+}
+
+func resourceDatabaseTemporalHistoryRetentionRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_temporal_history_retention", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getTemporalHistoryRetentionConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	enabled, err := connector.GetTemporalHistoryRetention(ctx, database)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read temporal history retention of database [%s]", database))
+	}
+	if enabled == nil {
+		logger.Info().Msgf("No database [%s] found", database)
+		data.SetId("")
+	} else {
+		if err = data.Set(enabledProp, *enabled); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceDatabaseTemporalHistoryRetentionUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_temporal_history_retention", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	if data.HasChange(enabledProp) {
+		if err := setTemporalHistoryRetention(ctx, meta, data, data.Get(enabledProp).(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated temporal history retention on database [%s]", database)
+	}
+
+	return resourceDatabaseTemporalHistoryRetentionRead(ctx, data, meta)
+}
+
+func resourceDatabaseTemporalHistoryRetentionDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "database_temporal_history_retention", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	// Restore the default of new databases.
+	if err := setTemporalHistoryRetention(ctx, meta, data, true); err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Info().Msgf("reset temporal history retention on database [%s]", database)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+func setTemporalHistoryRetention(ctx context.Context, meta interface{}, data *schema.ResourceData, enabled bool) error {
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getTemporalHistoryRetentionConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	if err = connector.SetTemporalHistoryRetention(ctx, database, enabled); err != nil {
+		return errors.Wrapf(err, "unable to set temporal history retention on database [%s]", database)
+	}
+	return nil
+}
+
+func getTemporalHistoryRetentionConnector(meta interface{}, data *schema.ResourceData) (TemporalHistoryRetentionConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(TemporalHistoryRetentionConnector), nil
+}
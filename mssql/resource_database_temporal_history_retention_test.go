@@ -0,0 +1,48 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatabaseTemporalHistoryRetention_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "temporal_test")
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDatabaseTemporalHistoryRetention(t, "test", map[string]interface{}{"database": database, "enabled": "false"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_temporal_history_retention.test", "database", database),
+					resource.TestCheckResourceAttr("mssql_database_temporal_history_retention.test", "enabled", "false"),
+				),
+			},
+			{
+				Config: testAccCheckDatabaseTemporalHistoryRetention(t, "test", map[string]interface{}{"database": database, "enabled": "true"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_database_temporal_history_retention.test", "enabled", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabaseTemporalHistoryRetention(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_database_temporal_history_retention" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             enabled  = {{ .enabled }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
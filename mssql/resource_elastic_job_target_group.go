@@ -0,0 +1,278 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const targetGroupNameProp = "target_group_name"
+const memberProp = "member"
+const membershipTypeProp = "membership_type"
+const targetTypeProp = "target_type"
+const memberServerNameProp = "server_name"
+const memberDatabaseNameProp = "database_name"
+const elasticPoolNameProp = "elastic_pool_name"
+const refreshCredentialNameProp = "refresh_credential_name"
+
+type ElasticJobTargetGroupConnector interface {
+	GetElasticJobTargetGroup(ctx context.Context, database, name string) (*model.ElasticJobTargetGroup, error)
+	CreateElasticJobTargetGroup(ctx context.Context, database, name string) error
+	AddElasticJobTargetGroupMember(ctx context.Context, database, name string, member model.ElasticJobTargetGroupMember) error
+	DeleteElasticJobTargetGroupMember(ctx context.Context, database, name, targetID string) error
+	DeleteElasticJobTargetGroup(ctx context.Context, database, name string) error
+}
+
+func resourceElasticJobTargetGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceElasticJobTargetGroupCreate,
+		ReadContext:   resourceElasticJobTargetGroupRead,
+		UpdateContext: resourceElasticJobTargetGroupUpdate,
+		DeleteContext: resourceElasticJobTargetGroupDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			targetGroupNameProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			memberProp: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						membershipTypeProp: {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "Include",
+							ValidateFunc: validation.StringInSlice([]string{"Include", "Exclude"}, false),
+						},
+						targetTypeProp: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"SqlServer", "SqlElasticPool", "SqlDatabase"}, false),
+						},
+						memberServerNameProp: {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						memberDatabaseNameProp: {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						elasticPoolNameProp: {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						refreshCredentialNameProp: {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceElasticJobTargetGroupCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "elastic_job_target_group", "create")
+	logger.Debug().Msgf("Create %s", getElasticJobTargetGroupID(data))
+
+	database := data.Get(databaseProp).(string)
+	name := data.Get(targetGroupNameProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	connector, err := getElasticJobTargetGroupConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.CreateElasticJobTargetGroup(ctx, database, name); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to create target group [%s] in job database [%s]", name, database))
+	}
+
+	data.SetId(getElasticJobTargetGroupID(data))
+
+	if err = setElasticJobTargetGroupMembers(ctx, connector, data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	logger.Info().Msgf("created target group [%s] in job database [%s]", name, database)
+
+	return resourceElasticJobTargetGroupRead(ctx, data, meta)
+}
+
+func resourceElasticJobTargetGroupRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "elastic_job_target_group", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	name := data.Get(targetGroupNameProp).(string)
+
+	connector, err := getElasticJobTargetGroupConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	group, err := connector.GetElasticJobTargetGroup(ctx, database, name)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read target group [%s] in job database [%s]", name, database))
+	}
+	if group == nil {
+		logger.Info().Msgf("No target group [%s] found in job database [%s]", name, database)
+		data.SetId("")
+		return nil
+	}
+
+	members := make([]map[string]interface{}, len(group.Members))
+	for i, member := range group.Members {
+		members[i] = map[string]interface{}{
+			membershipTypeProp:        member.MembershipType,
+			targetTypeProp:            member.TargetType,
+			memberServerNameProp:      member.ServerName,
+			memberDatabaseNameProp:    member.DatabaseName,
+			elasticPoolNameProp:       member.ElasticPoolName,
+			refreshCredentialNameProp: member.RefreshCredentialName,
+		}
+	}
+	if err = data.Set(memberProp, members); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceElasticJobTargetGroupUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "elastic_job_target_group", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	if data.HasChange(memberProp) {
+		connector, err := getElasticJobTargetGroupConnector(meta, data)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err = setElasticJobTargetGroupMembers(ctx, connector, data); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated members of target group [%s]", data.Get(targetGroupNameProp).(string))
+	}
+
+	return resourceElasticJobTargetGroupRead(ctx, data, meta)
+}
+
+func resourceElasticJobTargetGroupDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "elastic_job_target_group", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	name := data.Get(targetGroupNameProp).(string)
+
+	connector, err := getElasticJobTargetGroupConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.DeleteElasticJobTargetGroup(ctx, database, name); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to delete target group [%s] in job database [%s]", name, database))
+	}
+
+	logger.Info().Msgf("deleted target group [%s] in job database [%s]", name, database)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// setElasticJobTargetGroupMembers deletes the members of the target group that are not configured, and adds
+// the configured members that are missing. Members cannot be altered, so a changed member is replaced.
+func setElasticJobTargetGroupMembers(ctx context.Context, connector ElasticJobTargetGroupConnector, data *schema.ResourceData) error {
+	database := data.Get(databaseProp).(string)
+	name := data.Get(targetGroupNameProp).(string)
+
+	group, err := connector.GetElasticJobTargetGroup(ctx, database, name)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read target group [%s] in job database [%s]", name, database)
+	}
+	if group == nil {
+		return errors.Errorf("target group [%s] does not exist in job database [%s]", name, database)
+	}
+
+	desired := make(map[string]model.ElasticJobTargetGroupMember)
+	for _, m := range data.Get(memberProp).(*schema.Set).List() {
+		m := m.(map[string]interface{})
+		member := model.ElasticJobTargetGroupMember{
+			MembershipType:        m[membershipTypeProp].(string),
+			TargetType:            m[targetTypeProp].(string),
+			ServerName:            m[memberServerNameProp].(string),
+			DatabaseName:          m[memberDatabaseNameProp].(string),
+			ElasticPoolName:       m[elasticPoolNameProp].(string),
+			RefreshCredentialName: m[refreshCredentialNameProp].(string),
+		}
+		desired[elasticJobTargetGroupMemberKey(member)] = member
+	}
+
+	for _, member := range group.Members {
+		key := elasticJobTargetGroupMemberKey(member)
+		if _, ok := desired[key]; ok {
+			delete(desired, key)
+			continue
+		}
+		if err = connector.DeleteElasticJobTargetGroupMember(ctx, database, name, member.TargetID); err != nil {
+			return errors.Wrapf(err, "unable to delete member [%s] from target group [%s]", key, name)
+		}
+	}
+	for key, member := range desired {
+		if err = connector.AddElasticJobTargetGroupMember(ctx, database, name, member); err != nil {
+			return errors.Wrapf(err, "unable to add member [%s] to target group [%s]", key, name)
+		}
+	}
+	return nil
+}
+
+func elasticJobTargetGroupMemberKey(member model.ElasticJobTargetGroupMember) string {
+	return strings.Join([]string{member.MembershipType, member.TargetType, strings.ToLower(member.ServerName),
+		strings.ToLower(member.DatabaseName), strings.ToLower(member.ElasticPoolName), member.RefreshCredentialName}, "/")
+}
+
+func getElasticJobTargetGroupID(data *schema.ResourceData) string {
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	database := data.Get(databaseProp).(string)
+	name := data.Get(targetGroupNameProp).(string)
+	return fmt.Sprintf("sqlserver://%s:%s/%s/target_group/%s", host, port, database, name)
+}
+
+func getElasticJobTargetGroupConnector(meta interface{}, data *schema.ResourceData) (ElasticJobTargetGroupConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(ElasticJobTargetGroupConnector), nil
+}
@@ -2,10 +2,15 @@ package mssql
 
 import (
   "context"
+  "fmt"
+  "regexp"
   "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
   "github.com/pkg/errors"
+  "strconv"
   "strings"
+  "time"
   "github.com/betr-io/terraform-provider-mssql/mssql/model"
 )
 
@@ -13,12 +18,24 @@ const loginNameProp = "login_name"
 const defaultDatabaseProp = "default_database"
 const defaultDatabaseDefault = "master"
 const defaultLanguageProp = "default_language"
+const loginTypeProp = "login_type"
+const loginTypeSQL = "SQL"
+const loginTypeWindows = "WINDOWS"
+const loginTypeExternal = "EXTERNAL"
+const strictDefaultDatabaseProp = "strict_default_database"
+const checkPolicyProp = "check_policy"
+const checkExpirationProp = "check_expiration"
+const mustChangePasswordProp = "must_change_password"
+const passwordHashedProp = "password_hashed"
 
 type LoginConnector interface {
-  CreateLogin(ctx context.Context, name, password, defaultDatabase, defaultLanguage string) error
+  CreateLogin(ctx context.Context, login *model.Login) (string, error)
   GetLogin(ctx context.Context, name string) (*model.Login, error)
-  UpdateLogin(ctx context.Context, name, password, defaultDatabase, defaultLanguage string) error
+  UpdateLogin(ctx context.Context, login *model.Login) (string, error)
   DeleteLogin(ctx context.Context, name string) error
+  LoginHasDatabaseAccess(ctx context.Context, database, name string) (bool, error)
+  VerifyLoginAccess(ctx context.Context, name, defaultDatabase string) ([]string, error)
+  LoginPasswordMatches(ctx context.Context, name, password string) (bool, error)
 }
 
 func resourceLogin() *schema.Resource {
@@ -30,6 +47,7 @@ func resourceLogin() *schema.Resource {
     Importer: &schema.ResourceImporter{
       StateContext: resourceLoginImport,
     },
+    CustomizeDiff: resourceLoginCustomizeDiff,
     Schema: map[string]*schema.Schema{
       serverProp: {
         Type:         schema.TypeList,
@@ -40,15 +58,50 @@ func resourceLogin() *schema.Resource {
         },
       },
       loginNameProp: {
-        Type:     schema.TypeString,
-        Required: true,
-        ForceNew: true,
+        Type:             schema.TypeString,
+        Required:         true,
+        ForceNew:         true,
+        StateFunc:        trimName,
+        DiffSuppressFunc: suppressNameWhitespaceDiff,
+      },
+      loginTypeProp: {
+        Type:         schema.TypeString,
+        Optional:     true,
+        ForceNew:     true,
+        Default:      loginTypeSQL,
+        ValidateFunc: validation.StringInSlice([]string{loginTypeSQL, loginTypeWindows, loginTypeExternal}, false),
+      },
+      objectIdProp: {
+        Type:         schema.TypeString,
+        Optional:     true,
+        ForceNew:     true,
+        ValidateFunc: validation.IsUUID,
       },
       passwordProp: {
         Type:      schema.TypeString,
-        Required:  true,
+        Optional:  true,
         Sensitive: true,
       },
+      passwordHashedProp: {
+        Type:     schema.TypeBool,
+        Optional: true,
+        Default:  false,
+      },
+      checkPolicyProp: {
+        Type:     schema.TypeBool,
+        Optional: true,
+        Default:  true,
+      },
+      checkExpirationProp: {
+        Type:     schema.TypeBool,
+        Optional: true,
+        Default:  false,
+      },
+      mustChangePasswordProp: {
+        Type:     schema.TypeBool,
+        Optional: true,
+        Default:  false,
+      },
       defaultDatabaseProp: {
         Type:     schema.TypeString,
         Optional: true,
@@ -57,6 +110,11 @@ func resourceLogin() *schema.Resource {
           return (old == "" && new == defaultDatabaseDefault) || (old == defaultDatabaseDefault && new == "")
         },
       },
+      strictDefaultDatabaseProp: {
+        Type:     schema.TypeBool,
+        Optional: true,
+        Default:  false,
+      },
       defaultLanguageProp: {
         Type:     schema.TypeString,
         Optional: true,
@@ -68,6 +126,40 @@ func resourceLogin() *schema.Resource {
         Type:     schema.TypeInt,
         Computed: true,
       },
+      serverNameProp: {
+        Type:     schema.TypeString,
+        Computed: true,
+      },
+      passwordHashProp: {
+        Type:      schema.TypeString,
+        Computed:  true,
+        Sensitive: true,
+      },
+      verifyAccessProp: {
+        Type:     schema.TypeBool,
+        Optional: true,
+        Default:  false,
+      },
+      tagsProp: {
+        Type:     schema.TypeMap,
+        Optional: true,
+        Elem: &schema.Schema{
+          Type: schema.TypeString,
+        },
+      },
+      trackModificationsProp: {
+        Type:     schema.TypeBool,
+        Optional: true,
+        Default:  false,
+      },
+      modifyDateProp: {
+        Type:     schema.TypeString,
+        Computed: true,
+      },
+      lastAppliedSqlProp: {
+        Type:     schema.TypeString,
+        Computed: true,
+      },
     },
     Timeouts: &schema.ResourceTimeout{
       Default: defaultTimeout,
@@ -79,55 +171,109 @@ func resourceLoginCreate(ctx context.Context, data *schema.ResourceData, meta in
   logger := loggerFromMeta(meta, "login", "create")
   logger.Debug().Msgf("Create %s", getLoginID(data))
 
-  loginName := data.Get(loginNameProp).(string)
-  password := data.Get(passwordProp).(string)
-  defaultDatabase := data.Get(defaultDatabaseProp).(string)
-  defaultLanguage := data.Get(defaultLanguageProp).(string)
+  loginName := trimName(data.Get(loginNameProp))
+
+  login := getLoginFromData(data)
+  if err := validateLogin(login); err != nil {
+    return diag.FromErr(err)
+  }
+  if login.DefaultDatabase != "" {
+    if err := checkDatabaseExists(ctx, meta, data, login.DefaultDatabase); err != nil {
+      return diag.FromErr(err)
+    }
+  }
 
   connector, err := getLoginConnector(meta, data)
   if err != nil {
     return diag.FromErr(err)
   }
 
-  if err = connector.CreateLogin(ctx, loginName, password, defaultDatabase, defaultLanguage); err != nil {
+  // A previous apply may have created the login without saving it to state, in which case it is adopted
+  // and aligned with the configuration, as long as it matches what would have been created, including its
+  // password or SID.
+  existing, err := connector.GetLogin(ctx, loginName)
+  if err != nil {
+    return diag.FromErr(errors.Wrapf(err, "unable to look up login [%s]", loginName))
+  }
+  var applied string
+  if existing != nil {
+    if err = checkExistingLogin(existing, login); err != nil {
+      return diag.FromErr(err)
+    }
+    if login.LoginType == loginTypeSQL && !login.PasswordHashed {
+      matches, err := connector.LoginPasswordMatches(ctx, loginName, login.Password)
+      if err != nil {
+        return diag.FromErr(errors.Wrapf(err, "login [%s] already exists, and its password cannot be compared; import it with terraform import, or drop it, before applying", loginName))
+      }
+      if !matches {
+        return diag.Errorf("login [%s] already exists with another %s; import it with terraform import, or drop it, before applying", loginName, passwordProp)
+      }
+    }
+    logger.Info().Msgf("adopting existing login [%s]", loginName)
+    applied, err = connector.UpdateLogin(ctx, login)
+  } else {
+    applied, err = connector.CreateLogin(ctx, login)
+  }
+  if err != nil {
     return diag.FromErr(errors.Wrapf(err, "unable to create login [%s]", loginName))
   }
+  if err = data.Set(lastAppliedSqlProp, applied); err != nil {
+    return diag.FromErr(err)
+  }
 
   data.SetId(getLoginID(data))
 
   logger.Info().Msgf("created login [%s]", loginName)
 
-  return resourceLoginRead(ctx, data, meta)
+  diags := checkDefaultDatabaseAccess(ctx, connector, data, login)
+  if err = verifyLoginAccess(ctx, connector, data, login, data.Timeout(schema.TimeoutCreate)); err != nil {
+    return append(diags, diag.FromErr(err)...)
+  }
+  return append(diags, resourceLoginRead(ctx, data, meta)...)
 }
 
 func resourceLoginRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
   logger := loggerFromMeta(meta, "login", "read")
   logger.Debug().Msgf("Read %s", getLoginID(data))
 
-  loginName := data.Get(loginNameProp).(string)
+  loginName := trimName(data.Get(loginNameProp))
 
   connector, err := getLoginConnector(meta, data)
   if err != nil {
     return diag.FromErr(err)
   }
 
+  // The server name is read along with the login, and only looked up separately when the login is gone.
   login, err := connector.GetLogin(ctx, loginName)
   if err != nil {
-    return diag.FromErr(errors.Wrapf(err, "unable to read login [%s]", loginName))
+    return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read login [%s]", loginName))
   }
   if login == nil {
+    if err = checkServerName(ctx, meta, data); err != nil {
+      return readFailed(ctx, meta, data, err)
+    }
     logger.Info().Msgf("No login found for [%s]", loginName)
     data.SetId("")
   } else {
+    if err = setServerName(data, login.ServerName); err != nil {
+      return diag.FromErr(err)
+    }
     if err = data.Set(principalIdProp, login.PrincipalID); err != nil {
       return diag.FromErr(err)
     }
+    if err = data.Set(loginTypeProp, login.LoginType); err != nil {
+      return diag.FromErr(err)
+    }
     if err = data.Set(defaultDatabaseProp, login.DefaultDatabase); err != nil {
       return diag.FromErr(err)
     }
     if err = data.Set(defaultLanguageProp, login.DefaultLanguage); err != nil {
       return diag.FromErr(err)
     }
+    if err = data.Set(passwordHashProp, login.PasswordHash); err != nil {
+      return diag.FromErr(err)
+    }
+    return checkModifyDate(data, fmt.Sprintf("login [%s]", loginName), login.ModifyDate)
   }
 
   return nil
@@ -137,30 +283,56 @@ func resourceLoginUpdate(ctx context.Context, data *schema.ResourceData, meta in
   logger := loggerFromMeta(meta, "login", "update")
   logger.Debug().Msgf("Update %s", data.Id())
 
-  loginName := data.Get(loginNameProp).(string)
-  password := data.Get(passwordProp).(string)
-  defaultDatabase := data.Get(defaultDatabaseProp).(string)
-  defaultLanguage := data.Get(defaultLanguageProp).(string)
+  loginName := trimName(data.Get(loginNameProp))
+
+  if !data.HasChangeExcept(tagsProp) {
+    // tags only live in state, so there is nothing to change on the server
+    return nil
+  }
+
+  login := getLoginFromData(data)
+  if err := validateLogin(login); err != nil {
+    return diag.FromErr(err)
+  }
+  if data.HasChange(defaultDatabaseProp) && login.DefaultDatabase != "" {
+    if err := checkDatabaseExists(ctx, meta, data, login.DefaultDatabase); err != nil {
+      return diag.FromErr(err)
+    }
+  }
 
   connector, err := getLoginConnector(meta, data)
   if err != nil {
     return diag.FromErr(err)
   }
 
-  if err = connector.UpdateLogin(ctx, loginName, password, defaultDatabase, defaultLanguage); err != nil {
+  applied, err := connector.UpdateLogin(ctx, login)
+  if err != nil {
     return diag.FromErr(errors.Wrapf(err, "unable to update login [%s]", loginName))
   }
+  if err = data.Set(lastAppliedSqlProp, applied); err != nil {
+    return diag.FromErr(err)
+  }
 
   logger.Info().Msgf("updated login [%s]", loginName)
 
-  return resourceLoginRead(ctx, data, meta)
+  var diags diag.Diagnostics
+  if data.HasChanges(defaultDatabaseProp, strictDefaultDatabaseProp) {
+    diags = checkDefaultDatabaseAccess(ctx, connector, data, login)
+  }
+  if err = verifyLoginAccess(ctx, connector, data, login, data.Timeout(schema.TimeoutUpdate)); err != nil {
+    return append(diags, diag.FromErr(err)...)
+  }
+  if err = data.Set(modifyDateProp, ""); err != nil {
+    return append(diags, diag.FromErr(err)...)
+  }
+  return append(diags, resourceLoginRead(ctx, data, meta)...)
 }
 
 func resourceLoginDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
   logger := loggerFromMeta(meta, "login", "delete")
   logger.Debug().Msgf("Delete %s", data.Id())
 
-  loginName := data.Get(loginNameProp).(string)
+  loginName := trimName(data.Get(loginNameProp))
 
   connector, err := getLoginConnector(meta, data)
   if err != nil {
@@ -201,7 +373,7 @@ func resourceLoginImport(ctx context.Context, data *schema.ResourceData, meta in
 
   data.SetId(getLoginID(data))
 
-  loginName := data.Get(loginNameProp).(string)
+  loginName := trimName(data.Get(loginNameProp))
 
   connector, err := getLoginConnector(meta, data)
   if err != nil {
@@ -220,16 +392,214 @@ func resourceLoginImport(ctx context.Context, data *schema.ResourceData, meta in
   if err = data.Set(principalIdProp, login.PrincipalID); err != nil {
     return nil, err
   }
+  if err = data.Set(loginTypeProp, login.LoginType); err != nil {
+    return nil, err
+  }
   if err = data.Set(defaultDatabaseProp, login.DefaultDatabase); err != nil {
     return nil, err
   }
   if err = data.Set(defaultLanguageProp, login.DefaultLanguage); err != nil {
     return nil, err
   }
+  if err = data.Set(passwordHashProp, login.PasswordHash); err != nil {
+    return nil, err
+  }
+  if err = data.Set(modifyDateProp, login.ModifyDate); err != nil {
+    return nil, err
+  }
+  if err = data.Set(strictDefaultDatabaseProp, false); err != nil {
+    return nil, err
+  }
+  if err = data.Set(verifyAccessProp, false); err != nil {
+    return nil, err
+  }
+  if err = data.Set(trackModificationsProp, false); err != nil {
+    return nil, err
+  }
+  if err = data.Set(passwordHashedProp, false); err != nil {
+    return nil, err
+  }
+  if err = data.Set(checkPolicyProp, true); err != nil {
+    return nil, err
+  }
+  if err = data.Set(checkExpirationProp, false); err != nil {
+    return nil, err
+  }
+  if err = data.Set(mustChangePasswordProp, false); err != nil {
+    return nil, err
+  }
 
   return []*schema.ResourceData{data}, nil
 }
 
+func getLoginFromData(data *schema.ResourceData) *model.Login {
+  return &model.Login{
+    LoginName:       trimName(data.Get(loginNameProp)),
+    LoginType:       data.Get(loginTypeProp).(string),
+    Password:        data.Get(passwordProp).(string),
+    ObjectId:        data.Get(objectIdProp).(string),
+    DefaultDatabase: data.Get(defaultDatabaseProp).(string),
+    DefaultLanguage: data.Get(defaultLanguageProp).(string),
+    PasswordHashed:  data.Get(passwordHashedProp).(bool),
+    CheckPolicy:     data.Get(checkPolicyProp).(bool),
+    CheckExpiration: data.Get(checkExpirationProp).(bool),
+    // MUST_CHANGE only applies when the password is set, or the login would have to change it after every update
+    MustChange: data.Get(mustChangePasswordProp).(bool) && (data.IsNewResource() || data.HasChange(passwordProp)),
+  }
+}
+
+// checkDefaultDatabaseAccess warns when the login has no access to its default database, as it will then
+// be unable to connect without specifying a database. With strict_default_database it is an error instead.
+func checkDefaultDatabaseAccess(ctx context.Context, connector LoginConnector, data *schema.ResourceData, login *model.Login) diag.Diagnostics {
+  if login.DefaultDatabase == "" || login.DefaultDatabase == defaultDatabaseDefault {
+    return nil
+  }
+  access, err := connector.LoginHasDatabaseAccess(ctx, login.DefaultDatabase, login.LoginName)
+  if err != nil {
+    return diag.FromErr(errors.Wrapf(err, "unable to check access of login [%s] to database [%s]", login.LoginName, login.DefaultDatabase))
+  }
+  if access {
+    return nil
+  }
+  severity := diag.Warning
+  if data.Get(strictDefaultDatabaseProp).(bool) {
+    severity = diag.Error
+  }
+  return diag.Diagnostics{{
+    Severity: severity,
+    Summary:  fmt.Sprintf("login [%s] has no access to its default database [%s]", login.LoginName, login.DefaultDatabase),
+    Detail:   "The login will be unable to connect unless it specifies another database. Create a user for the login in the database.",
+  }}
+}
+
+// verifyLoginAccess checks with verify_access that the login can connect to the server and its default database.
+func verifyLoginAccess(ctx context.Context, connector LoginConnector, data *schema.ResourceData, login *model.Login, timeout time.Duration) error {
+  if !data.Get(verifyAccessProp).(bool) {
+    return nil
+  }
+  return verifyAccess(ctx, fmt.Sprintf("login [%s]", login.LoginName), timeout, func() ([]string, error) {
+    return connector.VerifyLoginAccess(ctx, login.LoginName, login.DefaultDatabase)
+  })
+}
+
+func validateLogin(login *model.Login) error {
+  if login.ObjectId != "" && login.LoginType != loginTypeExternal {
+    return errors.Errorf("%s can only be set for %s logins", objectIdProp, loginTypeExternal)
+  }
+  switch login.LoginType {
+  case loginTypeWindows, loginTypeExternal:
+    if login.Password != "" {
+      return errors.Errorf("%s cannot be set for %s logins", passwordProp, login.LoginType)
+    }
+  default:
+    if login.Password == "" {
+      return errors.Errorf("%s is required for %s logins", passwordProp, loginTypeSQL)
+    }
+  }
+  return validateLoginPasswordOptions(login.LoginType, login.Password, login.PasswordHashed, login.CheckPolicy, login.CheckExpiration, login.MustChange)
+}
+
+func resourceLoginCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+  loginType := diff.Get(loginTypeProp).(string)
+  hashed := diff.Get(passwordHashedProp).(bool)
+  checkPolicy := diff.Get(checkPolicyProp).(bool)
+  checkExpiration := diff.Get(checkExpirationProp).(bool)
+  mustChange := diff.Get(mustChangePasswordProp).(bool)
+
+  // The password may not be known until apply, in which case its format is checked on create or update
+  if err := validateLoginPasswordOptions(loginType, diff.Get(passwordProp).(string), hashed, checkPolicy, checkExpiration, mustChange); err != nil {
+    return err
+  }
+
+  // Only connect to the server if the configuration depends on what it supports
+  if loginType != loginTypeExternal && !hashed && checkPolicy && !checkExpiration && !mustChange {
+    return nil
+  }
+  edition, majorVersion, ok := getPlannedServerVersion(ctx, diff, meta)
+  if !ok {
+    return nil
+  }
+  return validateLoginServerSupport(edition, majorVersion, loginType, hashed, checkPolicy, checkExpiration, mustChange)
+}
+
+// checkExistingLogin checks that a login that already exists is the one the configuration would have
+// created, so that it can be adopted. A plain text password is compared by the server with PWDCOMPARE
+// afterwards; a hashed one is compared with the hash of the login here.
+func checkExistingLogin(existing, login *model.Login) error {
+  defaultDatabase := login.DefaultDatabase
+  if defaultDatabase == "" {
+    defaultDatabase = defaultDatabaseDefault
+  }
+  switch {
+  case existing.LoginType != login.LoginType:
+    return existingLoginConflict(login.LoginName, loginTypeProp, existing.LoginType, login.LoginType)
+  case !strings.EqualFold(existing.DefaultDatabase, defaultDatabase):
+    return existingLoginConflict(login.LoginName, defaultDatabaseProp, existing.DefaultDatabase, defaultDatabase)
+  case login.DefaultLanguage != "" && !strings.EqualFold(existing.DefaultLanguage, login.DefaultLanguage):
+    return existingLoginConflict(login.LoginName, defaultLanguageProp, existing.DefaultLanguage, login.DefaultLanguage)
+  }
+  if login.LoginType != loginTypeSQL {
+    return checkExistingSID(fmt.Sprintf("login [%s]", login.LoginName), existing.SIDStr, login.ObjectId)
+  }
+  switch {
+  case existing.CheckPolicy != login.CheckPolicy:
+    return existingLoginConflict(login.LoginName, checkPolicyProp, strconv.FormatBool(existing.CheckPolicy), strconv.FormatBool(login.CheckPolicy))
+  case existing.CheckExpiration != login.CheckExpiration:
+    return existingLoginConflict(login.LoginName, checkExpirationProp, strconv.FormatBool(existing.CheckExpiration), strconv.FormatBool(login.CheckExpiration))
+  case login.PasswordHashed && existing.PasswordHash == "":
+    return errors.Errorf("login [%s] already exists, and its password hash cannot be read to compare it; import it with terraform import, or drop it, before applying", login.LoginName)
+  case login.PasswordHashed && !strings.EqualFold(existing.PasswordHash, login.Password):
+    return errors.Errorf("login [%s] already exists with another password hash; import it with terraform import, or drop it, before applying", login.LoginName)
+  }
+  return nil
+}
+
+func existingLoginConflict(loginName, prop, existing, configured string) error {
+  return errors.Errorf("login [%s] already exists with %s [%s] instead of [%s]; import it with terraform import, or drop it, before applying", loginName, prop, existing, configured)
+}
+
+var passwordHashRegexp = regexp.MustCompile(`^0x[0-9A-Fa-f]+$`)
+
+// validateLoginPasswordOptions checks the combination of password options, which SQL Server otherwise rejects
+// with messages that do not say which option to change. Whether the server supports the options at all is
+// checked by validateLoginServerSupport.
+func validateLoginPasswordOptions(loginType, password string, hashed, checkPolicy, checkExpiration, mustChange bool) error {
+  if loginType != loginTypeSQL {
+    if hashed || !checkPolicy || checkExpiration || mustChange {
+      return errors.Errorf("%s, %s, %s and %s only apply to %s logins", passwordHashedProp, checkPolicyProp, checkExpirationProp, mustChangePasswordProp, loginTypeSQL)
+    }
+    return nil
+  }
+  if checkExpiration && !checkPolicy {
+    return errors.Errorf("%s requires %s, as the expiration period is taken from the Windows password policy", checkExpirationProp, checkPolicyProp)
+  }
+  if mustChange && !(checkPolicy && checkExpiration) {
+    return errors.Errorf("%s requires both %s and %s, as SQL Server enforces the change through the password policy", mustChangePasswordProp, checkPolicyProp, checkExpirationProp)
+  }
+  if hashed {
+    if mustChange {
+      return errors.Errorf("%s cannot be combined with %s, as SQL Server only accepts MUST_CHANGE with a plain text password", mustChangePasswordProp, passwordHashedProp)
+    }
+    if password != "" && !passwordHashRegexp.MatchString(password) {
+      return errors.Errorf("with %s, %s must be a password hash as a hex string, e.g. the password_hash of another login (0x0200...)", passwordHashedProp, passwordProp)
+    }
+  }
+  return nil
+}
+
+// validateLoginServerSupport checks that the server, given by SERVERPROPERTY('EngineEdition') and
+// SERVERPROPERTY('ProductMajorVersion'), supports the login type and password options. CreateLogin and
+// UpdateLogin check the same on apply, for servers that cannot be reached when planning.
+func validateLoginServerSupport(edition, majorVersion int, loginType string, hashed, checkPolicy, checkExpiration, mustChange bool) error {
+  if loginType == loginTypeExternal && edition != 5 && edition != 8 && majorVersion < 16 {
+    return errors.Errorf("%s logins require Azure SQL Managed Instance, Azure SQL Database or SQL Server 2022", loginTypeExternal)
+  }
+  if edition == 5 && (hashed || !checkPolicy || checkExpiration || mustChange) {
+    return errors.Errorf("Azure SQL Database does not support %s, %s, %s or %s, as its logins always follow its own password policy", passwordHashedProp, checkPolicyProp, checkExpirationProp, mustChangePasswordProp)
+  }
+  return nil
+}
+
 func getLoginConnector(meta interface{}, data *schema.ResourceData) (LoginConnector, error) {
   provider := meta.(model.Provider)
   connector, err := provider.GetConnector(serverProp, data)
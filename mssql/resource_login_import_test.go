@@ -23,7 +23,7 @@ func TestAccLogin_Local_BasicImport(t *testing.T) {
         ResourceName:            "mssql_login.test_import",
         ImportState:             true,
         ImportStateVerify:       true,
-        ImportStateVerifyIgnore: []string{"password"},
+        ImportStateVerifyIgnore: []string{"password", "last_applied_sql"},
         ImportStateIdFunc:       testAccImportStateId("mssql_login.test_import", false),
       },
     },
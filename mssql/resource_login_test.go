@@ -1,10 +1,17 @@
 package mssql
 
 import (
+  "context"
   "fmt"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
   "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+  mssqldb "github.com/microsoft/go-mssqldb"
+  "github.com/rs/zerolog"
   "os"
+  "regexp"
+  "strings"
   "testing"
 )
 
@@ -32,12 +39,76 @@ func TestAccLogin_Local_Basic(t *testing.T) {
           resource.TestCheckResourceAttr("mssql_login.basic", "server.0.login.0.password", os.Getenv("MSSQL_PASSWORD")),
           resource.TestCheckResourceAttr("mssql_login.basic", "server.0.azure_login.#", "0"),
           resource.TestCheckResourceAttrSet("mssql_login.basic", "principal_id"),
+          resource.TestCheckResourceAttrSet("mssql_login.basic", "password_hash"),
+          resource.TestCheckResourceAttrSet("mssql_login.basic", "server_name"),
+          resource.TestCheckResourceAttrSet("mssql_login.basic", "modify_date"),
+          resource.TestCheckResourceAttr("mssql_login.basic", "last_applied_sql", "CREATE LOGIN [login_basic] WITH PASSWORD = '***'"),
         ),
       },
     },
   })
 }
 
+func TestAccLogin_Local_WindowsWithPassword(t *testing.T) {
+  resource.Test(t, resource.TestCase{
+    PreCheck:          func() { testAccPreCheck(t) },
+    IsUnitTest:        runLocalAccTests,
+    ProviderFactories: testAccProviders,
+    CheckDestroy:      func(state *terraform.State) error { return testAccCheckLoginDestroy(state) },
+    Steps: []resource.TestStep{
+      {
+        Config:      testAccCheckLogin(t, "windows", false, map[string]interface{}{"login_name": "DOMAIN\\\\login_windows", "login_type": "WINDOWS", "password": "valueIsH8kd$¡"}),
+        ExpectError: regexp.MustCompile("password cannot be set for WINDOWS logins"),
+      },
+    },
+  })
+}
+
+func TestAccLogin_Local_ExternalWithPassword(t *testing.T) {
+  resource.Test(t, resource.TestCase{
+    PreCheck:          func() { testAccPreCheck(t) },
+    IsUnitTest:        runLocalAccTests,
+    ProviderFactories: testAccProviders,
+    CheckDestroy:      func(state *terraform.State) error { return testAccCheckLoginDestroy(state) },
+    Steps: []resource.TestStep{
+      {
+        Config:      testAccCheckLogin(t, "external", false, map[string]interface{}{"login_name": "login_external@example.com", "login_type": "EXTERNAL", "password": "valueIsH8kd$¡"}),
+        ExpectError: regexp.MustCompile("password cannot be set for EXTERNAL logins"),
+      },
+    },
+  })
+}
+
+func TestAccLogin_Local_MissingDefaultDatabase(t *testing.T) {
+  resource.Test(t, resource.TestCase{
+    PreCheck:          func() { testAccPreCheck(t) },
+    IsUnitTest:        runLocalAccTests,
+    ProviderFactories: testAccProviders,
+    CheckDestroy:      func(state *terraform.State) error { return testAccCheckLoginDestroy(state) },
+    Steps: []resource.TestStep{
+      {
+        Config:      testAccCheckLogin(t, "missing_db", false, map[string]interface{}{"login_name": "login_missing_db", "password": "valueIsH8kd$¡", "default_database": "missing_db"}),
+        ExpectError: regexp.MustCompile("database \\[missing_db\\] does not exist"),
+      },
+    },
+  })
+}
+
+func TestAccLogin_Local_StrictDefaultDatabase(t *testing.T) {
+  resource.Test(t, resource.TestCase{
+    PreCheck:          func() { testAccPreCheck(t) },
+    IsUnitTest:        runLocalAccTests,
+    ProviderFactories: testAccProviders,
+    CheckDestroy:      func(state *terraform.State) error { return testAccCheckLoginDestroy(state) },
+    Steps: []resource.TestStep{
+      {
+        Config:      testAccCheckLogin(t, "strict", false, map[string]interface{}{"login_name": "login_strict", "password": "valueIsH8kd$¡", "default_database": "model", "strict_default_database": true}),
+        ExpectError: regexp.MustCompile("login \\[login_strict\\] has no access to its default database \\[model\\]"),
+      },
+    },
+  })
+}
+
 func TestAccLogin_Azure_Basic(t *testing.T) {
   resource.Test(t, resource.TestCase{
     PreCheck:          func() { testAccPreCheck(t) },
@@ -171,6 +242,31 @@ func TestAccLogin_Local_UpdateDefaultLanguage(t *testing.T) {
     }})
 }
 
+func TestAccLogin_Local_UpdateTags(t *testing.T) {
+  resource.Test(t, resource.TestCase{
+    PreCheck:          func() { testAccPreCheck(t) },
+    IsUnitTest:        runLocalAccTests,
+    ProviderFactories: testAccProviders,
+    CheckDestroy:      func(state *terraform.State) error { return testAccCheckLoginDestroy(state) },
+    Steps: []resource.TestStep{
+      {
+        Config: testAccCheckLogin(t, "test_tags", false, map[string]interface{}{"login_name": "login_tags", "password": "valueIsH8kd$¡", "tags": `{ team = "data" }`}),
+        Check: resource.ComposeTestCheckFunc(
+          resource.TestCheckResourceAttr("mssql_login.test_tags", "tags.team", "data"),
+          testAccCheckLoginExists("mssql_login.test_tags"),
+        ),
+      },
+      {
+        Config: testAccCheckLogin(t, "test_tags", false, map[string]interface{}{"login_name": "login_tags", "password": "valueIsH8kd$¡", "tags": `{ team = "platform" }`}),
+        Check: resource.ComposeTestCheckFunc(
+          resource.TestCheckResourceAttr("mssql_login.test_tags", "tags.team", "platform"),
+          // No SQL is run when only tags change
+          resource.TestCheckResourceAttr("mssql_login.test_tags", "last_applied_sql", "CREATE LOGIN [login_tags] WITH PASSWORD = '***'"),
+        ),
+      },
+    }})
+}
+
 func TestAccLogin_Azure_UpdateLoginName(t *testing.T) {
   resource.Test(t, resource.TestCase{
     PreCheck:          func() { testAccPreCheck(t) },
@@ -224,9 +320,12 @@ func testAccCheckLogin(t *testing.T, name string, azure bool, data map[string]in
                {{ if .azure }}azure_login {}{{ else }}login {}{{ end }}
              }
              login_name = "{{ .login_name }}"
-             password   = "{{ .password }}"
+             {{ with .login_type }}login_type = "{{ . }}"{{ end }}
+             {{ with .password }}password = "{{ . }}"{{ end }}
              {{ with .default_database }}default_database = "{{ . }}"{{ end }}
+             {{ with .strict_default_database }}strict_default_database = {{ . }}{{ end }}
              {{ with .default_language }}default_language = "{{ . }}"{{ end }}
+             {{ with .tags }}tags = {{ . }}{{ end }}
            }`
   data["name"] = name
   data["azure"] = azure
@@ -338,3 +437,223 @@ func testAccCheckLoginWorks(resource string) resource.TestCheckFunc {
     return nil
   }
 }
+
+// countingLoginConnector counts the queries a read of mssql_login makes.
+type countingLoginConnector struct {
+  LoginConnector
+  login   *model.Login
+  err     error
+  missing bool
+  queries int
+}
+
+func (c *countingLoginConnector) GetLogin(context.Context, string) (*model.Login, error) {
+  c.queries++
+  return c.login, c.err
+}
+
+func (c *countingLoginConnector) GetServerName(context.Context) (string, error) {
+  c.queries++
+  return "sqlserver", nil
+}
+
+func (c *countingLoginConnector) DatabaseMissing(context.Context, string) (bool, error) {
+  c.queries++
+  return c.missing, nil
+}
+
+func (c *countingLoginConnector) PrincipalMissing(context.Context, string, string) (bool, error) {
+  c.queries++
+  return c.missing, nil
+}
+
+type countingProvider struct {
+  model.Provider
+  connector            interface{}
+  ignoreMissingObjects bool
+}
+
+func (p countingProvider) GetConnector(string, *schema.ResourceData) (interface{}, error) {
+  return p.connector, nil
+}
+
+func (p countingProvider) ResourceLogger(string, string) zerolog.Logger {
+  return zerolog.Nop()
+}
+
+func (p countingProvider) IgnoreMissingObjects() bool {
+  return p.ignoreMissingObjects
+}
+
+func (p countingProvider) DefaultSchema() string {
+  return ""
+}
+
+func TestLoginRead_QueryCount(t *testing.T) {
+  for _, tc := range []struct {
+    name    string
+    login   *model.Login
+    queries int
+  }{
+    {"exists", &model.Login{LoginName: "login", LoginType: "SQL", ServerName: "sqlserver"}, 1},
+    {"missing", nil, 2},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      connector := &countingLoginConnector{login: tc.login}
+      data := schema.TestResourceDataRaw(t, resourceLogin().Schema, map[string]interface{}{"login_name": "login"})
+      data.SetId("sqlserver://localhost:1433/login")
+      if diags := resourceLoginRead(context.Background(), data, countingProvider{connector: connector}); diags.HasError() {
+        t.Fatalf("unexpected error: %v", diags)
+      }
+      if connector.queries != tc.queries {
+        t.Errorf("expected %d queries, got %d", tc.queries, connector.queries)
+      }
+      if got := data.Get(serverNameProp).(string); got != "sqlserver" {
+        t.Errorf("expected server_name [sqlserver], got [%s]", got)
+      }
+    })
+  }
+}
+
+func TestLoginRead_IgnoreMissingObjects(t *testing.T) {
+  for _, tc := range []struct {
+    name     string
+    ignore   bool
+    err      error
+    missing  bool
+    hasError bool
+    id       string
+  }{
+    {"missing database", true, mssqldb.Error{Number: 911}, false, false, ""},
+    {"missing database without ignore", false, mssqldb.Error{Number: 911}, false, true, "sqlserver://localhost:1433/login"},
+    {"database of login not opened", true, mssqldb.Error{Number: 4060}, true, true, "sqlserver://localhost:1433/login"},
+    {"missing login", true, mssqldb.Error{Number: 15151}, true, false, ""},
+    {"login without permission", true, mssqldb.Error{Number: 15151}, false, true, "sqlserver://localhost:1433/login"},
+    {"other error", true, mssqldb.Error{Number: 50000}, false, true, "sqlserver://localhost:1433/login"},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      connector := &countingLoginConnector{err: tc.err, missing: tc.missing}
+      data := schema.TestResourceDataRaw(t, resourceLogin().Schema, map[string]interface{}{"login_name": "login"})
+      data.SetId("sqlserver://localhost:1433/login")
+      diags := resourceLoginRead(context.Background(), data, countingProvider{connector: connector, ignoreMissingObjects: tc.ignore})
+      if diags.HasError() != tc.hasError {
+        t.Errorf("expected error %t, got %v", tc.hasError, diags)
+      }
+      if data.Id() != tc.id {
+        t.Errorf("expected id [%s], got [%s]", tc.id, data.Id())
+      }
+    })
+  }
+}
+
+func TestValidateLoginPasswordOptions(t *testing.T) {
+  for _, tc := range []struct {
+    name            string
+    loginType       string
+    password        string
+    hashed          bool
+    checkPolicy     bool
+    checkExpiration bool
+    mustChange      bool
+    err             string
+  }{
+    {"defaults", loginTypeSQL, "valueIsH8kd$¡", false, true, false, false, ""},
+    {"policy off", loginTypeSQL, "valueIsH8kd$¡", false, false, false, false, ""},
+    {"must change", loginTypeSQL, "valueIsH8kd$¡", false, true, true, true, ""},
+    {"hashed", loginTypeSQL, "0x0200ABCDEF", true, true, false, false, ""},
+    {"hashed unknown password", loginTypeSQL, "", true, false, false, false, ""},
+    {"expiration without policy", loginTypeSQL, "valueIsH8kd$¡", false, false, true, false, "check_expiration requires check_policy"},
+    {"must change without expiration", loginTypeSQL, "valueIsH8kd$¡", false, true, false, true, "must_change_password requires both"},
+    {"must change hashed", loginTypeSQL, "0x0200ABCDEF", true, true, true, true, "cannot be combined with password_hashed"},
+    {"hashed plain text", loginTypeSQL, "valueIsH8kd$¡", true, true, false, false, "must be a password hash"},
+    {"windows defaults", loginTypeWindows, "", false, true, false, false, ""},
+    {"windows policy off", loginTypeWindows, "", false, false, false, false, "only apply to SQL logins"},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      err := validateLoginPasswordOptions(tc.loginType, tc.password, tc.hashed, tc.checkPolicy, tc.checkExpiration, tc.mustChange)
+      if tc.err == "" && err != nil {
+        t.Errorf("unexpected error: %v", err)
+      }
+      if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+        t.Errorf("expected error containing [%s], got %v", tc.err, err)
+      }
+    })
+  }
+}
+
+func TestValidateLoginServerSupport(t *testing.T) {
+  for _, tc := range []struct {
+    name            string
+    edition         int
+    majorVersion    int
+    loginType       string
+    hashed          bool
+    checkPolicy     bool
+    checkExpiration bool
+    mustChange      bool
+    err             string
+  }{
+    {"sql server policy off", 3, 15, loginTypeSQL, false, false, false, false, ""},
+    {"sql server hashed", 2, 14, loginTypeSQL, true, true, false, false, ""},
+    {"sql server 2019 external", 3, 15, loginTypeExternal, false, true, false, false, "EXTERNAL logins require"},
+    {"sql server 2022 external", 3, 16, loginTypeExternal, false, true, false, false, ""},
+    {"managed instance external", 8, 12, loginTypeExternal, false, true, false, false, ""},
+    {"azure sql database external", 5, 12, loginTypeExternal, false, true, false, false, ""},
+    {"azure sql database defaults", 5, 12, loginTypeSQL, false, true, false, false, ""},
+    {"azure sql database hashed", 5, 12, loginTypeSQL, true, true, false, false, "Azure SQL Database does not support"},
+    {"azure sql database policy off", 5, 12, loginTypeSQL, false, false, false, false, "Azure SQL Database does not support"},
+    {"azure sql database must change", 5, 12, loginTypeSQL, false, true, true, true, "Azure SQL Database does not support"},
+    {"managed instance must change", 8, 12, loginTypeSQL, false, true, true, true, ""},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      err := validateLoginServerSupport(tc.edition, tc.majorVersion, tc.loginType, tc.hashed, tc.checkPolicy, tc.checkExpiration, tc.mustChange)
+      if tc.err == "" && err != nil {
+        t.Errorf("unexpected error: %v", err)
+      }
+      if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+        t.Errorf("expected error containing [%s], got %v", tc.err, err)
+      }
+    })
+  }
+}
+
+// testObjectId is an Azure AD object id, and testObjectSID the SID SQL Server derives from it.
+const (
+  testObjectId  = "6f8b1a2c-3d4e-5f60-7182-93a4b5c6d7e8"
+  testObjectSID = "0x2C1A8B6F4E3D605F718293A4B5C6D7E8"
+)
+
+func TestCheckExistingLogin(t *testing.T) {
+  for _, tc := range []struct {
+    name     string
+    existing model.Login
+    login    model.Login
+    err      string
+  }{
+    {"same", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "master", DefaultLanguage: "us_english", CheckPolicy: true}, model.Login{LoginType: loginTypeSQL, CheckPolicy: true}, ""},
+    {"same default database", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "App", CheckPolicy: true}, model.Login{LoginType: loginTypeSQL, DefaultDatabase: "app", CheckPolicy: true}, ""},
+    {"same default language", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "master", DefaultLanguage: "Deutsch", CheckPolicy: true}, model.Login{LoginType: loginTypeSQL, DefaultLanguage: "deutsch", CheckPolicy: true}, ""},
+    {"same hash", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "master", PasswordHash: "0x0200ABCDEF"}, model.Login{LoginType: loginTypeSQL, Password: "0x0200abcdef", PasswordHashed: true}, ""},
+    {"same external", model.Login{LoginType: loginTypeExternal, DefaultDatabase: "master", SIDStr: testObjectSID}, model.Login{LoginType: loginTypeExternal, ObjectId: testObjectId}, ""},
+    {"other type", model.Login{LoginType: loginTypeWindows, DefaultDatabase: "master"}, model.Login{LoginType: loginTypeSQL}, "login_type [WINDOWS] instead of [SQL]"},
+    {"other default database", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "app"}, model.Login{LoginType: loginTypeSQL}, "default_database [app] instead of [master]"},
+    {"other default language", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "master", DefaultLanguage: "us_english"}, model.Login{LoginType: loginTypeSQL, DefaultLanguage: "Deutsch"}, "default_language [us_english] instead of [Deutsch]"},
+    {"other check policy", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "master", CheckPolicy: false}, model.Login{LoginType: loginTypeSQL, CheckPolicy: true}, "check_policy [false] instead of [true]"},
+    {"other check expiration", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "master", CheckPolicy: true}, model.Login{LoginType: loginTypeSQL, CheckPolicy: true, CheckExpiration: true}, "check_expiration [false] instead of [true]"},
+    {"other hash", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "master", PasswordHash: "0x0200ABCDEF"}, model.Login{LoginType: loginTypeSQL, Password: "0x020012345678", PasswordHashed: true}, "another password hash"},
+    {"unreadable hash", model.Login{LoginType: loginTypeSQL, DefaultDatabase: "master"}, model.Login{LoginType: loginTypeSQL, Password: "0x020012345678", PasswordHashed: true}, "password hash cannot be read"},
+    {"windows", model.Login{LoginType: loginTypeWindows, DefaultDatabase: "master", SIDStr: "0x0105"}, model.Login{LoginType: loginTypeWindows}, "cannot be verified to be the same principal"},
+    {"other external", model.Login{LoginType: loginTypeExternal, DefaultDatabase: "master", SIDStr: "0x01"}, model.Login{LoginType: loginTypeExternal, ObjectId: testObjectId}, "SID [0x01] instead of"},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      tc.existing.LoginName, tc.login.LoginName = "login", "login"
+      err := checkExistingLogin(&tc.existing, &tc.login)
+      if tc.err == "" && err != nil {
+        t.Errorf("unexpected error: %v", err)
+      }
+      if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+        t.Errorf("expected error containing [%s], got %v", tc.err, err)
+      }
+    })
+  }
+}
@@ -0,0 +1,318 @@
+package mssql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const clientIPRangesProp = "client_ip_ranges"
+const allowedHoursProp = "allowed_hours"
+const fromProp = "from"
+const toProp = "to"
+const triggerNameProp = "trigger_name"
+const definitionProp = "definition"
+
+const logonTriggerPrefix = "logon_restriction_"
+
+var timeOfDayRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+type LogonTriggerConnector interface {
+	GetLogonTrigger(ctx context.Context, name string) (*model.LogonTrigger, error)
+	SetLogonTrigger(ctx context.Context, name, loginName, condition string) error
+	SetLogonTriggerEnabled(ctx context.Context, name string, enabled bool) error
+	DeleteLogonTrigger(ctx context.Context, name string) error
+}
+
+func resourceLogonTrigger() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLogonTriggerCreate,
+		ReadContext:   resourceLogonTriggerRead,
+		UpdateContext: resourceLogonTriggerUpdate,
+		DeleteContext: resourceLogonTriggerDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			loginNameProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			clientIPRangesProp: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIPv4CIDR,
+				},
+				AtLeastOneOf: []string{clientIPRangesProp, allowedHoursProp},
+			},
+			allowedHoursProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						fromProp: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringMatch(timeOfDayRegexp, "must be a time of day as HH:MM"),
+						},
+						toProp: {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringMatch(timeOfDayRegexp, "must be a time of day as HH:MM"),
+						},
+					},
+				},
+				AtLeastOneOf: []string{clientIPRangesProp, allowedHoursProp},
+			},
+			enabledProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			triggerNameProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			definitionProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceLogonTriggerCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "logon_trigger", "create")
+	logger.Debug().Msgf("Create %s", getLogonTriggerID(data))
+
+	triggerName := logonTriggerName(data.Get(loginNameProp).(string))
+
+	if err := setLogonTrigger(ctx, meta, data, triggerName); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getLogonTriggerID(data))
+
+	logger.Info().Msgf("created logon trigger [%s]", triggerName)
+
+	return resourceLogonTriggerRead(ctx, data, meta)
+}
+
+func resourceLogonTriggerRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "logon_trigger", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	triggerName := logonTriggerName(data.Get(loginNameProp).(string))
+
+	connector, err := getLogonTriggerConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	trigger, err := connector.GetLogonTrigger(ctx, triggerName)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read logon trigger [%s]", triggerName))
+	}
+	if trigger == nil {
+		logger.Info().Msgf("No logon trigger found for [%s]", triggerName)
+		data.SetId("")
+		return nil
+	}
+
+	if err = data.Set(enabledProp, !trigger.IsDisabled); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(triggerNameProp, trigger.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(definitionProp, trigger.Definition); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceLogonTriggerUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "logon_trigger", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	triggerName := logonTriggerName(data.Get(loginNameProp).(string))
+
+	if data.HasChanges(clientIPRangesProp, allowedHoursProp, enabledProp) {
+		if err := setLogonTrigger(ctx, meta, data, triggerName); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated logon trigger [%s]", triggerName)
+	}
+
+	return resourceLogonTriggerRead(ctx, data, meta)
+}
+
+func resourceLogonTriggerDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "logon_trigger", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	triggerName := logonTriggerName(data.Get(loginNameProp).(string))
+
+	connector, err := getLogonTriggerConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = connector.DeleteLogonTrigger(ctx, triggerName); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to delete logon trigger [%s]", triggerName))
+	}
+
+	logger.Info().Msgf("deleted logon trigger [%s]", triggerName)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// setLogonTrigger creates or alters the trigger from the configured restrictions, and then enables or
+// disables it.
+func setLogonTrigger(ctx context.Context, meta interface{}, data *schema.ResourceData, triggerName string) error {
+	connector, err := getLogonTriggerConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	var ranges []string
+	for _, r := range data.Get(clientIPRangesProp).(*schema.Set).List() {
+		ranges = append(ranges, r.(string))
+	}
+	var from, to string
+	if hours := data.Get(allowedHoursProp).([]interface{}); len(hours) == 1 && hours[0] != nil {
+		h := hours[0].(map[string]interface{})
+		from, to = h[fromProp].(string), h[toProp].(string)
+	}
+	condition, err := logonTriggerCondition(ranges, from, to)
+	if err != nil {
+		return err
+	}
+
+	if err = connector.SetLogonTrigger(ctx, triggerName, data.Get(loginNameProp).(string), condition); err != nil {
+		return errors.Wrapf(err, "unable to set logon trigger [%s]", triggerName)
+	}
+	if err = connector.SetLogonTriggerEnabled(ctx, triggerName, data.Get(enabledProp).(bool)); err != nil {
+		return errors.Wrapf(err, "unable to enable or disable logon trigger [%s]", triggerName)
+	}
+	return nil
+}
+
+// logonTriggerCondition builds the T-SQL condition under which a logon is allowed. It only contains numbers
+// parsed from the ranges and times of day, so it is safe to insert into the trigger body.
+func logonTriggerCondition(ranges []string, from, to string) (string, error) {
+	var conditions []string
+	if len(ranges) > 0 {
+		sort.Strings(ranges)
+		var between []string
+		for _, r := range ranges {
+			_, ipNet, err := net.ParseCIDR(r)
+			if err != nil || ipNet.IP.To4() == nil {
+				return "", fmt.Errorf("invalid IPv4 range [%s]", r)
+			}
+			first := binary.BigEndian.Uint32(ipNet.IP.To4())
+			last := first | ^binary.BigEndian.Uint32(net.IP(ipNet.Mask).To4())
+			between = append(between, fmt.Sprintf("@ip BETWEEN %d AND %d", first, last))
+		}
+		// @ip is NULL for IPv6 addresses and named pipes, which must not slip through as an unknown condition.
+		conditions = append(conditions, "(@ip IS NOT NULL AND ("+strings.Join(between, " OR ")+"))")
+	}
+	if from != "" && to != "" {
+		start, err := minuteOfDay(from)
+		if err != nil {
+			return "", err
+		}
+		end, err := minuteOfDay(to)
+		if err != nil {
+			return "", err
+		}
+		if start <= end {
+			conditions = append(conditions, fmt.Sprintf("(@minute >= %d AND @minute < %d)", start, end))
+		} else {
+			// The window wraps around midnight.
+			conditions = append(conditions, fmt.Sprintf("(@minute >= %d OR @minute < %d)", start, end))
+		}
+	}
+	if len(conditions) == 0 {
+		return "", errors.New("at least one of " + clientIPRangesProp + " and " + allowedHoursProp + " must be set")
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// logonTriggerName returns the name of the trigger restricting the login. Names that would exceed the 128
+// characters allowed for a trigger are truncated, and suffixed with a hash of the login name to keep them unique.
+func logonTriggerName(loginName string) string {
+	name := []rune(logonTriggerPrefix + loginName)
+	if len(name) <= 128 {
+		return string(name)
+	}
+	hash := sha256.Sum256([]byte(loginName))
+	suffix := "_" + hex.EncodeToString(hash[:4])
+	return string(name[:128-len(suffix)]) + suffix
+}
+
+func minuteOfDay(timeOfDay string) (int, error) {
+	var hour, minute int
+	if !timeOfDayRegexp.MatchString(timeOfDay) {
+		return 0, fmt.Errorf("invalid time of day [%s]", timeOfDay)
+	}
+	if _, err := fmt.Sscanf(timeOfDay, "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("invalid time of day [%s]", timeOfDay)
+	}
+	return hour*60 + minute, nil
+}
+
+func validateIPv4CIDR(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, ipNet, err := net.ParseCIDR(v); err != nil || ipNet.IP.To4() == nil {
+		return nil, []error{fmt.Errorf("expected %s to be an IPv4 range in CIDR notation, e.g. 10.0.0.0/8, got %s", k, v)}
+	}
+	return nil, nil
+}
+
+func getLogonTriggerID(data *schema.ResourceData) string {
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	loginName := data.Get(loginNameProp).(string)
+	return fmt.Sprintf("sqlserver://%s:%s/logon_trigger/%s", host, port, loginName)
+}
+
+func getLogonTriggerConnector(meta interface{}, data *schema.ResourceData) (LogonTriggerConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(LogonTriggerConnector), nil
+}
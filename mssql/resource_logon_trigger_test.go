@@ -0,0 +1,107 @@
+package mssql
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLogonTrigger_Local_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLogonTrigger(t, "test", map[string]interface{}{"login_name": "logon_trigger_test", "client_ip_ranges": `["10.0.0.0/8"]`, "enabled": "true"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_logon_trigger.test", "trigger_name", "logon_restriction_logon_trigger_test"),
+					resource.TestCheckResourceAttr("mssql_logon_trigger.test", "enabled", "true"),
+					resource.TestMatchResourceAttr("mssql_logon_trigger.test", "definition", regexp.MustCompile(`@ip BETWEEN 167772160 AND 184549375`)),
+				),
+			},
+			{
+				Config: testAccCheckLogonTrigger(t, "test", map[string]interface{}{"login_name": "logon_trigger_test", "client_ip_ranges": `["10.0.0.0/8"]`, "from": "08:00", "to": "18:00", "enabled": "false"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_logon_trigger.test", "enabled", "false"),
+					resource.TestMatchResourceAttr("mssql_logon_trigger.test", "definition", regexp.MustCompile(`@minute >= 480 AND @minute < 1080`)),
+				),
+			},
+		},
+	})
+}
+
+func TestLogonTriggerCondition(t *testing.T) {
+	tests := []struct {
+		ranges   []string
+		from, to string
+		expected string
+	}{
+		{[]string{"192.168.1.0/24", "10.0.0.1/32"}, "", "", "(@ip IS NOT NULL AND (@ip BETWEEN 167772161 AND 167772161 OR @ip BETWEEN 3232235776 AND 3232236031))"},
+		{nil, "08:00", "17:30", "(@minute >= 480 AND @minute < 1050)"},
+		{[]string{"10.0.0.0/8"}, "22:00", "06:00", "(@ip IS NOT NULL AND (@ip BETWEEN 167772160 AND 184549375)) AND (@minute >= 1320 OR @minute < 360)"},
+	}
+	for _, test := range tests {
+		condition, err := logonTriggerCondition(test.ranges, test.from, test.to)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if condition != test.expected {
+			t.Errorf("expected [%s], got [%s]", test.expected, condition)
+		}
+	}
+
+	if _, err := logonTriggerCondition([]string{"::1/128"}, "", ""); err == nil {
+		t.Errorf("expected error for IPv6 range")
+	}
+	if _, err := logonTriggerCondition(nil, "", ""); err == nil {
+		t.Errorf("expected error without restrictions")
+	}
+}
+
+func TestLogonTriggerName(t *testing.T) {
+	if name := logonTriggerName("app"); name != "logon_restriction_app" {
+		t.Errorf("expected [logon_restriction_app], got [%s]", name)
+	}
+
+	long := strings.Repeat("a", 128)
+	name := logonTriggerName(long)
+	if n := len([]rune(name)); n != 128 {
+		t.Errorf("expected trigger name of 128 characters, got %d", n)
+	}
+	if other := logonTriggerName(long[:127] + "b"); other == name {
+		t.Errorf("expected distinct trigger names for distinct long login names, got [%s] twice", name)
+	}
+}
+
+func testAccCheckLogonTrigger(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_login" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             login_name = "{{ .login_name }}"
+             password   = "valueIsH8kd$¡"
+           }
+           resource "mssql_logon_trigger" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             login_name       = mssql_login.{{ .name }}.login_name
+             client_ip_ranges = {{ .client_ip_ranges }}
+             {{ with .from }}allowed_hours {
+               from = "{{ . }}"
+               to   = "{{ $.to }}"
+             }{{ end }}
+             enabled          = {{ .enabled }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
@@ -0,0 +1,257 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pkg/errors"
+)
+
+const certificateNameProp = "certificate_name"
+const modulesProp = "modules"
+
+var moduleNameRegexp = regexp.MustCompile(`^[^.\[\]]+\.[^.\[\]]+$`)
+
+type ModuleSignatureConnector interface {
+	GetModuleSignatures(ctx context.Context, database, certificate string) (*model.ModuleSignature, error)
+	AddModuleSignature(ctx context.Context, database, certificate, schemaName, name, password string) error
+	DropModuleSignature(ctx context.Context, database, certificate, schemaName, name string) error
+}
+
+func resourceModuleSignature() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceModuleSignatureCreate,
+		ReadContext:   resourceModuleSignatureRead,
+		UpdateContext: resourceModuleSignatureUpdate,
+		DeleteContext: resourceModuleSignatureDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			certificateNameProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			modulesProp: {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(moduleNameRegexp, "must be a module name as schema.name"),
+				},
+			},
+			passwordProp: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceModuleSignatureCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "module_signature", "create")
+	logger.Debug().Msgf("Create %s", getModuleSignatureID(data))
+
+	database := data.Get(databaseProp).(string)
+	certificate := data.Get(certificateNameProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := checkDatabaseWritable(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	connector, err := getModuleSignatureConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = setModuleSignatures(ctx, connector, data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getModuleSignatureID(data))
+
+	logger.Info().Msgf("signed modules with certificate [%s] in database [%s]", certificate, database)
+
+	return resourceModuleSignatureRead(ctx, data, meta)
+}
+
+func resourceModuleSignatureRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "module_signature", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	certificate := data.Get(certificateNameProp).(string)
+
+	connector, err := getModuleSignatureConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	signature, err := connector.GetModuleSignatures(ctx, database, certificate)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read signatures by certificate [%s] in database [%s]", certificate, database))
+	}
+	if signature == nil {
+		logger.Info().Msgf("No certificate [%s] found in database [%s]", certificate, database)
+		data.SetId("")
+		return nil
+	}
+
+	// Keep the spelling of the configuration for modules that only differ in case from the catalog
+	configured := make(map[string]string)
+	for _, module := range data.Get(modulesProp).(*schema.Set).List() {
+		configured[strings.ToLower(module.(string))] = module.(string)
+	}
+	modules := make([]string, len(signature.Modules))
+	for i, module := range signature.Modules {
+		if m, ok := configured[strings.ToLower(module)]; ok {
+			module = m
+		}
+		modules[i] = module
+	}
+	if err = data.Set(modulesProp, modules); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceModuleSignatureUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "module_signature", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	if data.HasChange(modulesProp) {
+		if err := checkDatabaseWritable(ctx, meta, data, data.Get(databaseProp).(string)); err != nil {
+			return diag.FromErr(err)
+		}
+		connector, err := getModuleSignatureConnector(meta, data)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err = setModuleSignatures(ctx, connector, data); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated modules signed with certificate [%s]", data.Get(certificateNameProp).(string))
+	}
+
+	return resourceModuleSignatureRead(ctx, data, meta)
+}
+
+func resourceModuleSignatureDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "module_signature", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	certificate := data.Get(certificateNameProp).(string)
+
+	if err := checkDatabaseWritable(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	connector, err := getModuleSignatureConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, module := range data.Get(modulesProp).(*schema.Set).List() {
+		schemaName, name := splitModuleName(module.(string))
+		if err = connector.DropModuleSignature(ctx, database, certificate, schemaName, name); err != nil {
+			return diag.FromErr(errors.Wrapf(err, "unable to drop signature of [%s] by certificate [%s]", module, certificate))
+		}
+	}
+
+	logger.Info().Msgf("dropped signatures by certificate [%s] in database [%s]", certificate, database)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// setModuleSignatures drops the signatures by the certificate of the modules that are not configured, and signs
+// the configured modules that are not signed. Altering a module drops its signature, so it is signed again.
+func setModuleSignatures(ctx context.Context, connector ModuleSignatureConnector, data *schema.ResourceData) error {
+	database := data.Get(databaseProp).(string)
+	certificate := data.Get(certificateNameProp).(string)
+	password := data.Get(passwordProp).(string)
+
+	signature, err := connector.GetModuleSignatures(ctx, database, certificate)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read signatures by certificate [%s] in database [%s]", certificate, database)
+	}
+	if signature == nil {
+		return errors.Errorf("certificate [%s] does not exist in database [%s]", certificate, database)
+	}
+
+	desired := make(map[string]string)
+	for _, module := range data.Get(modulesProp).(*schema.Set).List() {
+		desired[strings.ToLower(module.(string))] = module.(string)
+	}
+
+	for _, module := range signature.Modules {
+		key := strings.ToLower(module)
+		if _, ok := desired[key]; ok {
+			delete(desired, key)
+			continue
+		}
+		schemaName, name := splitModuleName(module)
+		if err = connector.DropModuleSignature(ctx, database, certificate, schemaName, name); err != nil {
+			return errors.Wrapf(err, "unable to drop signature of [%s] by certificate [%s]", module, certificate)
+		}
+	}
+	for _, module := range desired {
+		schemaName, name := splitModuleName(module)
+		if err = connector.AddModuleSignature(ctx, database, certificate, schemaName, name, password); err != nil {
+			return errors.Wrapf(err, "unable to sign [%s] with certificate [%s]", module, certificate)
+		}
+	}
+	return nil
+}
+
+// splitModuleName splits a module name, as validated by moduleNameRegexp, into its schema and name.
+func splitModuleName(module string) (string, string) {
+	parts := strings.SplitN(module, ".", 2)
+	return parts[0], parts[1]
+}
+
+func getModuleSignatureID(data *schema.ResourceData) string {
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	database := data.Get(databaseProp).(string)
+	certificate := data.Get(certificateNameProp).(string)
+	return fmt.Sprintf("sqlserver://%s:%s/%s/module_signature/%s", host, port, database, certificate)
+}
+
+func getModuleSignatureConnector(meta interface{}, data *schema.ResourceData) (ModuleSignatureConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(ModuleSignatureConnector), nil
+}
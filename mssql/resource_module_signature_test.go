@@ -0,0 +1,88 @@
+package mssql
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/betr-io/terraform-provider-mssql/sql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccModuleSignature_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "signature_test")
+	testAccModuleSignatureSetup(t, database)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckModuleSignature(t, "test", map[string]interface{}{"database": database, "modules": `["dbo.proc_a"]`}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_module_signature.test", "modules.#", "1"),
+					resource.TestCheckTypeSetElemAttr("mssql_module_signature.test", "modules.*", "dbo.proc_a"),
+				),
+			},
+			{
+				Config: testAccCheckModuleSignature(t, "test", map[string]interface{}{"database": database, "modules": `["dbo.proc_a", "dbo.proc_b"]`}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_module_signature.test", "modules.#", "2"),
+				),
+			},
+			{
+				Config: testAccCheckModuleSignature(t, "test", map[string]interface{}{"database": database, "modules": `["dbo.proc_b"]`}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_module_signature.test", "modules.#", "1"),
+					resource.TestCheckTypeSetElemAttr("mssql_module_signature.test", "modules.*", "dbo.proc_b"),
+				),
+			},
+		},
+	})
+}
+
+// testAccModuleSignatureSetup creates a certificate and two procedures to sign in the database.
+func testAccModuleSignatureSetup(t *testing.T, database string) {
+	if !runLocalAccTests {
+		return
+	}
+	connector := &sql.Connector{
+		Host:     "localhost",
+		Port:     DefaultPort,
+		Database: database,
+		Timeout:  60 * time.Second,
+		Login: &sql.LoginUser{
+			Username: os.Getenv("MSSQL_USERNAME"),
+			Password: os.Getenv("MSSQL_PASSWORD"),
+		},
+	}
+	for _, cmd := range []string{
+		"CREATE MASTER KEY ENCRYPTION BY PASSWORD = 'Str0ng#Master#Key'",
+		"CREATE CERTIFICATE signing_cert WITH SUBJECT = 'Module signing'",
+		"CREATE PROCEDURE dbo.proc_a AS SELECT 1",
+		"CREATE PROCEDURE dbo.proc_b AS SELECT 2",
+	} {
+		if err := connector.ExecContext(context.Background(), cmd); err != nil {
+			t.Fatalf("unable to set up database %s: %s", database, err)
+		}
+	}
+}
+
+func testAccCheckModuleSignature(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_module_signature" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database         = "{{ .database }}"
+             certificate_name = "signing_cert"
+             modules          = {{ .modules }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
@@ -0,0 +1,202 @@
+package mssql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const createSqlProp = "create_sql"
+const readSqlProp = "read_sql"
+const deleteSqlProp = "delete_sql"
+const triggersProp = "triggers"
+
+type RawExecConnector interface {
+	ExecuteStatement(ctx context.Context, database, statement string) error
+	StatementReturnsRows(ctx context.Context, database, query string) (bool, error)
+	GetDefaultSchema(ctx context.Context, database string) (string, error)
+}
+
+func resourceRawExec() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRawExecCreate,
+		ReadContext:   resourceRawExecRead,
+		UpdateContext: resourceRawExecUpdate,
+		DeleteContext: resourceRawExecDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "master",
+			},
+			createSqlProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			readSqlProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			deleteSqlProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			defaultSchemaProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			triggersProp: {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceRawExecCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "raw_exec", "create")
+
+	database := data.Get(databaseProp).(string)
+	createSql := data.Get(createSqlProp).(string)
+
+	connector, err := getRawExecConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = checkRawExecSchema(ctx, meta, connector, data); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = connector.ExecuteStatement(ctx, database, createSql); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to execute create statement in [%s]", database))
+	}
+
+	data.SetId(getTriggeredID(data, "raw_exec"))
+
+	logger.Info().Msgf("executed create statement %s", data.Id())
+
+	return resourceRawExecRead(ctx, data, meta)
+}
+
+func resourceRawExecRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "raw_exec", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	readSql := data.Get(readSqlProp).(string)
+
+	if readSql == "" {
+		return nil
+	}
+
+	connector, err := getRawExecConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = checkRawExecSchema(ctx, meta, connector, data); err != nil {
+		return readFailed(ctx, meta, data, err)
+	}
+	found, err := connector.StatementReturnsRows(ctx, database, readSql)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to execute read statement in [%s]", database))
+	}
+	if !found {
+		logger.Info().Msgf("Read statement returned no rows for %s", data.Id())
+		data.SetId("")
+	}
+
+	return nil
+}
+
+func resourceRawExecUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "raw_exec", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	// Only read_sql and delete_sql can change in place, and they are not executed until needed.
+
+	return resourceRawExecRead(ctx, data, meta)
+}
+
+func resourceRawExecDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "raw_exec", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+	deleteSql := data.Get(deleteSqlProp).(string)
+
+	if deleteSql != "" {
+		connector, err := getRawExecConnector(meta, data)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err = checkRawExecSchema(ctx, meta, connector, data); err != nil {
+			return diag.FromErr(err)
+		}
+		if err = connector.ExecuteStatement(ctx, database, deleteSql); err != nil {
+			return diag.FromErr(errors.Wrapf(err, "unable to execute delete statement in [%s]", database))
+		}
+
+		logger.Info().Msgf("executed delete statement %s", data.Id())
+	}
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// checkRawExecSchema fails when unqualified names in the statements would not resolve to default_schema, or
+// the default_schema of the provider if it is not set. The default schema belongs to the user a session runs
+// as, and no SET statement changes it, so it can only be verified here, or chosen by connecting as, or
+// impersonating with execute_as, a user with that default schema.
+func checkRawExecSchema(ctx context.Context, meta interface{}, connector RawExecConnector, data *schema.ResourceData) error {
+	expected := data.Get(defaultSchemaProp).(string)
+	if expected == "" {
+		expected = meta.(model.Provider).DefaultSchema()
+	}
+	if expected == "" {
+		return nil
+	}
+	database := data.Get(databaseProp).(string)
+	actual, err := connector.GetDefaultSchema(ctx, database)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read default schema in [%s]", database)
+	}
+	if !strings.EqualFold(actual, expected) {
+		return errors.Errorf("unqualified names resolve to schema [%s] in [%s], not [%s]; change the default schema of the connecting user, impersonate a user with that default schema with execute_as, or qualify the names in the statements",
+			actual, database, expected)
+	}
+	return nil
+}
+
+func getRawExecConnector(meta interface{}, data *schema.ResourceData) (RawExecConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(RawExecConnector), nil
+}
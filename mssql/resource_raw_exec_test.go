@@ -0,0 +1,135 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const rawExecReadSql = "SELECT 1 FROM [sys].[tables] WHERE [name] = 'raw_exec_test'"
+
+func TestAccRawExec_Local_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckRawExecDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckRawExec(t, "basic", map[string]interface{}{"trigger": "1"}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRawExecExists("mssql_raw_exec.basic"),
+					resource.TestCheckResourceAttr("mssql_raw_exec.basic", "database", "master"),
+					resource.TestCheckResourceAttr("mssql_raw_exec.basic", "triggers.version", "1"),
+				),
+			},
+			{
+				Config: testAccCheckRawExec(t, "basic", map[string]interface{}{"trigger": "2"}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRawExecExists("mssql_raw_exec.basic"),
+					resource.TestCheckResourceAttr("mssql_raw_exec.basic", "triggers.version", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRawExec_Local_DefaultSchema(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckRawExecDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckRawExec(t, "schema", map[string]interface{}{"trigger": "1", "default_schema": "sys"}),
+				ExpectError: regexp.MustCompile(`unqualified names resolve to schema \[dbo\] in \[master\], not \[sys\]`),
+			},
+			{
+				Config:      testAccCheckRawExec(t, "schema", map[string]interface{}{"trigger": "1", "provider_default_schema": "sys"}),
+				ExpectError: regexp.MustCompile(`unqualified names resolve to schema \[dbo\] in \[master\], not \[sys\]`),
+			},
+			{
+				Config: testAccCheckRawExec(t, "schema", map[string]interface{}{"trigger": "1", "default_schema": "dbo", "provider_default_schema": "sys"}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckRawExecExists("mssql_raw_exec.schema"),
+					resource.TestCheckResourceAttr("mssql_raw_exec.schema", "default_schema", "dbo"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckRawExec(t *testing.T, name string, data map[string]interface{}) string {
+	text := `{{ with .provider_default_schema }}provider "mssql" {
+             default_schema = "{{ . }}"
+           }
+           {{ end }}resource "mssql_raw_exec" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             create_sql = "CREATE TABLE [dbo].[raw_exec_test] ([id] int)"
+             read_sql   = "{{ .read_sql }}"
+             delete_sql = "DROP TABLE [dbo].[raw_exec_test]"
+             {{ with .default_schema }}default_schema = "{{ . }}"{{ end }}
+             triggers = {
+               version = "{{ .trigger }}"
+             }
+           }`
+	data["name"] = name
+	data["read_sql"] = rawExecReadSql
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
+
+func testAccCheckRawExecFound(attributes map[string]string) (bool, error) {
+	connector, err := getTestConnector(attributes)
+	if err != nil {
+		return false, err
+	}
+	return connector.(testConnector).c.(RawExecConnector).StatementReturnsRows(context.Background(), attributes[databaseProp], rawExecReadSql)
+}
+
+func testAccCheckRawExecDestroy(state *terraform.State) error {
+	for _, rs := range state.RootModule().Resources {
+		if rs.Type != "mssql_raw_exec" {
+			continue
+		}
+		found, err := testAccCheckRawExecFound(rs.Primary.Attributes)
+		if err != nil {
+			return fmt.Errorf("expected no error, got %s", err)
+		}
+		if found {
+			return fmt.Errorf("table still exists")
+		}
+	}
+	return nil
+}
+
+func testAccCheckRawExecExists(resource string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("not found: %s", resource)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no record ID is set")
+		}
+		found, err := testAccCheckRawExecFound(rs.Primary.Attributes)
+		if err != nil {
+			return fmt.Errorf("expected no error, got %s", err)
+		}
+		if !found {
+			return fmt.Errorf("table does not exist")
+		}
+		return nil
+	}
+}
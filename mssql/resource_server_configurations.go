@@ -0,0 +1,198 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const settingsProp = "settings"
+const pendingRestartProp = "pending_restart"
+
+type ConfigurationConnector interface {
+	GetConfigurations(ctx context.Context) ([]model.Configuration, error)
+	SetConfigurations(ctx context.Context, settings map[string]int64) error
+}
+
+func resourceServerConfigurations() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerConfigurationsCreate,
+		ReadContext:   resourceServerConfigurationsRead,
+		UpdateContext: resourceServerConfigurationsUpdate,
+		DeleteContext: resourceServerConfigurationsDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			settingsProp: {
+				Type:     schema.TypeMap,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			pendingRestartProp: {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceServerConfigurationsCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "server_configurations", "create")
+	logger.Debug().Msgf("Create %s", getServerConfigurationsID(data))
+
+	if err := applyServerConfigurations(ctx, meta, data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getServerConfigurationsID(data))
+
+	logger.Info().Msgf("applied server configurations")
+
+	return resourceServerConfigurationsRead(ctx, data, meta)
+}
+
+func resourceServerConfigurationsRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "server_configurations", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	connector, err := getConfigurationConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	configurations, err := connector.GetConfigurations(ctx)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrap(err, "unable to read server configurations"))
+	}
+
+	settings := make(map[string]interface{})
+	pendingRestart := make([]string, 0)
+	for name := range data.Get(settingsProp).(map[string]interface{}) {
+		configuration := findConfiguration(configurations, name)
+		if configuration == nil {
+			logger.Info().Msgf("No server configuration found for [%s]", name)
+			continue
+		}
+		settings[name] = int(configuration.Value)
+		if configuration.Value != configuration.ValueInUse {
+			pendingRestart = append(pendingRestart, name)
+		}
+	}
+	sort.Strings(pendingRestart)
+
+	if err = data.Set(settingsProp, settings); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set(pendingRestartProp, pendingRestart); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceServerConfigurationsUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "server_configurations", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	if data.HasChange(settingsProp) {
+		if err := applyServerConfigurations(ctx, meta, data); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("applied server configurations")
+	}
+
+	return resourceServerConfigurationsRead(ctx, data, meta)
+}
+
+func resourceServerConfigurationsDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "server_configurations", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	// The settings are left as they are, as there is no record of the values they should revert to.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// applyServerConfigurations sets the settings that differ from the server in one batch. Unknown settings
+// are reported before anything is changed.
+func applyServerConfigurations(ctx context.Context, meta interface{}, data *schema.ResourceData) error {
+	connector, err := getConfigurationConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	configurations, err := connector.GetConfigurations(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to read server configurations")
+	}
+
+	settings := make(map[string]int64)
+	var unknown []string
+	for name, value := range data.Get(settingsProp).(map[string]interface{}) {
+		configuration := findConfiguration(configurations, name)
+		if configuration == nil {
+			unknown = append(unknown, name)
+		} else if configuration.Value != int64(value.(int)) {
+			settings[configuration.Name] = int64(value.(int))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown server configuration options [%s]", strings.Join(unknown, "], ["))
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+
+	if err = connector.SetConfigurations(ctx, settings); err != nil {
+		return errors.Wrap(err, "unable to apply server configurations")
+	}
+	return nil
+}
+
+func findConfiguration(configurations []model.Configuration, name string) *model.Configuration {
+	for i := range configurations {
+		if strings.EqualFold(configurations[i].Name, name) {
+			return &configurations[i]
+		}
+	}
+	return nil
+}
+
+func getServerConfigurationsID(data *schema.ResourceData) string {
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	return fmt.Sprintf("sqlserver://%s:%s/configurations", host, port)
+}
+
+func getConfigurationConnector(meta interface{}, data *schema.ResourceData) (ConfigurationConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(ConfigurationConnector), nil
+}
@@ -0,0 +1,64 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccServerConfigurations_Local_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckServerConfigurations(t, "basic", map[string]interface{}{"settings": `{ "cost threshold for parallelism" = 50, "remote access" = 1 }`}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_server_configurations.basic", "settings.%", "2"),
+					resource.TestCheckResourceAttr("mssql_server_configurations.basic", "settings.cost threshold for parallelism", "50"),
+					resource.TestCheckResourceAttr("mssql_server_configurations.basic", "pending_restart.#", "0"),
+				),
+			},
+			{
+				// Restore the default
+				Config: testAccCheckServerConfigurations(t, "basic", map[string]interface{}{"settings": `{ "cost threshold for parallelism" = 5 }`}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_server_configurations.basic", "settings.%", "1"),
+					resource.TestCheckResourceAttr("mssql_server_configurations.basic", "settings.cost threshold for parallelism", "5"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccServerConfigurations_Local_Unknown(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckServerConfigurations(t, "unknown", map[string]interface{}{"settings": `{ "no such option" = 1 }`}),
+				ExpectError: regexp.MustCompile(`unknown server configuration options \[no such option\]`),
+			},
+		},
+	})
+}
+
+func testAccCheckServerConfigurations(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_server_configurations" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             settings = {{ .settings }}
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
@@ -2,7 +2,11 @@ package mssql
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/betr-io/terraform-provider-mssql/mssql/model"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -11,7 +15,7 @@ import (
 )
 
 func resourceUser() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
 		UpdateContext: resourceUserUpdate,
@@ -34,6 +38,137 @@ func resourceUser() *schema.Resource {
 				ForceNew: true,
 				Default:  "master",
 			},
+			usernameProp: {
+				Type:             schema.TypeString,
+				Required:         true,
+				StateFunc:        trimName,
+				DiffSuppressFunc: suppressNameWhitespaceDiff,
+			},
+			objectIdProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			loginNameProp: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				ForceNew:  true,
+				StateFunc: trimName,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// The login name is read back from the server, with the case of the login
+					return strings.EqualFold(strings.TrimSpace(old), strings.TrimSpace(new))
+				},
+			},
+			passwordProp: {
+				Type:      schema.TypeString,
+				Optional:  true,
+				ForceNew:  true,
+				Sensitive: true,
+			},
+			sidStrProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			authenticationTypeProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			principalIdProp: {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			serverNameProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			defaultSchemaProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  defaultSchemaPropDefault,
+			},
+			defaultLanguageProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				DiffSuppressFunc: func(k, old, new string, data *schema.ResourceData) bool {
+					return data.Get(authenticationTypeProp) == "INSTANCE" || old == new
+				},
+			},
+			rolesProp: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			createMissingRolesProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			reassignOwnedToProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "dbo",
+			},
+			verifyAccessProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			tagsProp: {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			trackModificationsProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			modifyDateProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			lastAppliedSqlProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+	r.SchemaVersion = 1
+	r.StateUpgraders = []schema.StateUpgrader{{
+		Version: 0,
+		Type:    resourceUserV0().CoreConfigSchema().ImpliedType(),
+		Upgrade: resourceUserStateUpgradeV0,
+	}}
+	return r
+}
+
+// resourceUserV0 is the schema of mssql_user as released before its ID named the principal id rather than the
+// user. It must not be changed along with resourceUser.
+func resourceUserV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchemaV0(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "master",
+			},
 			usernameProp: {
 				Type:     schema.TypeString,
 				Required: true,
@@ -87,27 +222,32 @@ func resourceUser() *schema.Resource {
 				},
 			},
 		},
-		Timeouts: &schema.ResourceTimeout{
-			Default: defaultTimeout,
-		},
 	}
 }
 
 type UserConnector interface {
-	CreateUser(ctx context.Context, database string, user *model.User) error
+	CreateUser(ctx context.Context, database string, user *model.User) (string, error)
 	GetUser(ctx context.Context, database, username string) (*model.User, error)
-	UpdateUser(ctx context.Context, database string, user *model.User) error
+	UpdateUser(ctx context.Context, database string, user *model.User) (string, error)
 	DeleteUser(ctx context.Context, database, username string) error
+	GetDatabaseRoles(ctx context.Context, database string) ([]string, error)
+	CreateDatabaseRole(ctx context.Context, database, role string) error
+	GetUserOwnedSecurables(ctx context.Context, database, username string) ([]string, error)
+	ReassignUserOwnership(ctx context.Context, database, username, owner string) (string, error)
+	RevokeUserGrants(ctx context.Context, database, username string) (string, error)
+	VerifyUserAccess(ctx context.Context, database, username string, roles []string) ([]string, error)
+	GetUserNameByPrincipalID(ctx context.Context, database string, principalID int64, sid string) (string, error)
+	RenameUser(ctx context.Context, database, username, newUsername string) (string, error)
 }
 
 func resourceUserCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	logger := loggerFromMeta(meta, "user", "create")
-	logger.Debug().Msgf("Create %s", getUserID(data))
 
 	database := data.Get(databaseProp).(string)
-	username := data.Get(usernameProp).(string)
+	username := trimName(data.Get(usernameProp))
+	logger.Debug().Msgf("Create [%s].[%s]", database, username)
 	objectId := data.Get(objectIdProp).(string)
-	loginName := data.Get(loginNameProp).(string)
+	loginName := trimName(data.Get(loginNameProp))
 	password := data.Get(passwordProp).(string)
 	defaultSchema := data.Get(defaultSchemaProp).(string)
 	defaultLanguage := data.Get(defaultLanguageProp).(string)
@@ -116,6 +256,15 @@ func resourceUserCreate(ctx context.Context, data *schema.ResourceData, meta int
 	if loginName != "" && password != "" {
 		return diag.Errorf(loginNameProp + " and " + passwordProp + " cannot both be set")
 	}
+	if loginName == "" && password == "" && objectId == "" {
+		loginName = defaultUserLoginName(ctx, meta, data, username)
+	} else if loginName != "" {
+		resolved, err := resolveLoginName(ctx, meta, data, loginName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		loginName = resolved
+	}
 	var authType string
 	if loginName != "" {
 		authType = "INSTANCE"
@@ -127,6 +276,12 @@ func resourceUserCreate(ctx context.Context, data *schema.ResourceData, meta int
 	if defaultSchema == "" {
 		return diag.Errorf(defaultSchemaProp + " cannot be empty")
 	}
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := checkDatabaseWritable(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
 
 	connector, err := getUserConnector(meta, data)
 	if err != nil {
@@ -143,14 +298,51 @@ func resourceUserCreate(ctx context.Context, data *schema.ResourceData, meta int
 		DefaultLanguage: defaultLanguage,
 		Roles:           toStringSlice(roles),
 	}
-	if err = connector.CreateUser(ctx, database, user); err != nil {
+	if err = ensureRolesExist(ctx, connector, data, database, user.Roles); err != nil {
+		return diag.FromErr(err)
+	}
+	// A previous apply may have created the user without saving it to state, in which case it is adopted
+	// and its roles are aligned with the configuration, as long as it matches what would have been created.
+	existing, err := connector.GetUser(ctx, database, username)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to look up user [%s].[%s]", database, username))
+	}
+	var applied string
+	if existing != nil {
+		if err = checkExistingUser(database, existing, user); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("adopting existing user [%s].[%s]", database, username)
+		applied, err = connector.UpdateUser(ctx, database, user)
+	} else {
+		applied, err = connector.CreateUser(ctx, database, user)
+	}
+	if err != nil {
 		return diag.FromErr(errors.Wrapf(err, "unable to create user [%s].[%s]", database, username))
 	}
+	if err = data.Set(lastAppliedSqlProp, applied); err != nil {
+		return diag.FromErr(err)
+	}
+
+	created, err := connector.GetUser(ctx, database, username)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read user [%s].[%s]", database, username))
+	}
+	if created == nil {
+		return diag.Errorf("user [%s].[%s] not found after it was created", database, username)
+	}
+	if err = data.Set(principalIdProp, created.PrincipalID); err != nil {
+		return diag.FromErr(err)
+	}
 
 	data.SetId(getUserID(data))
 
 	logger.Info().Msgf("created user [%s].[%s]", database, username)
 
+	if err = verifyUserAccess(ctx, connector, data, user, data.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceUserRead(ctx, data, meta)
 }
 
@@ -159,21 +351,47 @@ func resourceUserRead(ctx context.Context, data *schema.ResourceData, meta inter
 	logger.Debug().Msgf("Read %s", data.Id())
 
 	database := data.Get(databaseProp).(string)
-	username := data.Get(usernameProp).(string)
+	username := trimName(data.Get(usernameProp))
 
 	connector, err := getUserConnector(meta, data)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// The server name is read along with the user, and only looked up separately when the user is gone.
 	user, err := connector.GetUser(ctx, database, username)
 	if err != nil {
-		return diag.FromErr(errors.Wrapf(err, "unable to read user [%s].[%s]", database, username))
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read user [%s].[%s]", database, username))
 	}
 	if user == nil {
+		// The user may have been renamed outside Terraform; find it by its principal id and SID.
+		if principalID := int64(data.Get(principalIdProp).(int)); principalID != 0 {
+			name, err := connector.GetUserNameByPrincipalID(ctx, database, principalID, data.Get(sidStrProp).(string))
+			if err != nil {
+				return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to look up user [%s].[%s] by principal id", database, username))
+			}
+			if name != "" {
+				logger.Info().Msgf("User [%s].[%s] was renamed to [%s]", database, username, name)
+				if user, err = connector.GetUser(ctx, database, name); err != nil {
+					return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read user [%s].[%s]", database, name))
+				}
+				if err = data.Set(usernameProp, name); err != nil {
+					return diag.FromErr(err)
+				}
+				username = name
+			}
+		}
+	}
+	if user == nil {
+		if err = checkServerName(ctx, meta, data); err != nil {
+			return readFailed(ctx, meta, data, err)
+		}
 		logger.Info().Msgf("No user found for [%s].[%s]", database, username)
 		data.SetId("")
 	} else {
+		if err = setServerName(data, user.ServerName); err != nil {
+			return diag.FromErr(err)
+		}
 		if err = data.Set(loginNameProp, user.LoginName); err != nil {
 			return diag.FromErr(err)
 		}
@@ -195,6 +413,9 @@ func resourceUserRead(ctx context.Context, data *schema.ResourceData, meta inter
 		if err = data.Set(rolesProp, user.Roles); err != nil {
 			return diag.FromErr(err)
 		}
+		// States upgraded without a principal id get the principal id based ID on their first read
+		data.SetId(getUserID(data))
+		return checkModifyDate(data, fmt.Sprintf("user [%s].[%s]", database, username), user.ModifyDate)
 	}
 
 	return nil
@@ -205,11 +426,20 @@ func resourceUserUpdate(ctx context.Context, data *schema.ResourceData, meta int
 	logger.Debug().Msgf("Update %s", data.Id())
 
 	database := data.Get(databaseProp).(string)
-	username := data.Get(usernameProp).(string)
+	username := trimName(data.Get(usernameProp))
 	defaultSchema := data.Get(defaultSchemaProp).(string)
 	defaultLanguage := data.Get(defaultLanguageProp).(string)
 	roles := data.Get(rolesProp).(*schema.Set).List()
 
+	if !data.HasChangeExcept(tagsProp) {
+		// tags only live in state, so there is nothing to change on the server
+		return nil
+	}
+
+	if err := checkDatabaseWritable(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
 	connector, err := getUserConnector(meta, data)
 	if err != nil {
 		return diag.FromErr(err)
@@ -221,14 +451,53 @@ func resourceUserUpdate(ctx context.Context, data *schema.ResourceData, meta int
 		DefaultLanguage: defaultLanguage,
 		Roles:           toStringSlice(roles),
 	}
-	if err = connector.UpdateUser(ctx, database, user); err != nil {
-		return diag.FromErr(errors.Wrapf(err, "unable to update user [%s].[%s]", database, username))
+
+	var applied []string
+	if data.HasChange(usernameProp) {
+		oldUsername, _ := data.GetChange(usernameProp)
+		stmt, err := connector.RenameUser(ctx, database, oldUsername.(string), username)
+		if err != nil {
+			return diag.FromErr(errors.Wrapf(err, "unable to rename user [%s].[%s] to [%s]", database, oldUsername, username))
+		}
+		applied = append(applied, stmt)
+		logger.Info().Msgf("renamed user [%s].[%s] to [%s]", database, oldUsername, username)
+	}
+
+	if data.HasChanges(defaultSchemaProp, defaultLanguageProp, rolesProp, createMissingRolesProp) {
+		if data.HasChanges(rolesProp, createMissingRolesProp) {
+			if err = ensureRolesExist(ctx, connector, data, database, user.Roles); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		stmt, err := connector.UpdateUser(ctx, database, user)
+		if err != nil {
+			return diag.FromErr(errors.Wrapf(err, "unable to update user [%s].[%s]", database, username))
+		}
+		if stmt != "" {
+			applied = append(applied, stmt)
+		}
+	} else if data.HasChange(usernameProp) {
+		// Only the name changed, so nothing else is altered, but check that the rename kept the rest of the user
+		if err = checkUserPreserved(ctx, connector, database, user); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err = data.Set(lastAppliedSqlProp, strings.Join(applied, "\n")); err != nil {
+		return diag.FromErr(err)
 	}
 
 	data.SetId(getUserID(data))
 
 	logger.Info().Msgf("updated user [%s].[%s]", database, username)
 
+	if err = verifyUserAccess(ctx, connector, data, user, data.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = data.Set(modifyDateProp, ""); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceUserRead(ctx, data, meta)
 }
 
@@ -237,13 +506,42 @@ func resourceUserDelete(ctx context.Context, data *schema.ResourceData, meta int
 	logger.Debug().Msgf("Delete %s", data.Id())
 
 	database := data.Get(databaseProp).(string)
-	username := data.Get(usernameProp).(string)
+	username := trimName(data.Get(usernameProp))
+
+	if err := checkDatabaseWritable(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
 
 	connector, err := getUserConnector(meta, data)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if owner := data.Get(reassignOwnedToProp).(string); owner != "" {
+		applied, err := connector.ReassignUserOwnership(ctx, database, username, owner)
+		if err != nil {
+			return diag.FromErr(errors.Wrapf(err, "unable to reassign securables owned by user [%s].[%s] to [%s]", database, username, owner))
+		}
+		// The resource leaves the state, so the statements run by delete are only logged
+		logger.Info().Msgf("reassigned securables owned by user [%s].[%s]: %s", database, username, applied)
+	}
+
+	// Check ownership first, so the user is left untouched if it cannot be dropped anyway
+	owned, err := connector.GetUserOwnedSecurables(ctx, database, username)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to read securables owned by user [%s].[%s]", database, username))
+	}
+	if len(owned) > 0 {
+		return diag.Errorf("unable to delete user [%s].[%s], which owns %s; set %s to transfer ownership",
+			database, username, strings.Join(owned, ", "), reassignOwnedToProp)
+	}
+
+	applied, err := connector.RevokeUserGrants(ctx, database, username)
+	if err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to revoke grants and role memberships of user [%s].[%s]", database, username))
+	}
+	logger.Info().Msgf("revoked grants and role memberships of user [%s].[%s]: %s", database, username, applied)
+
 	if err = connector.DeleteUser(ctx, database, username); err != nil {
 		return diag.FromErr(errors.Wrapf(err, "unable to delete user [%s].[%s]", database, username))
 	}
@@ -256,6 +554,25 @@ func resourceUserDelete(ctx context.Context, data *schema.ResourceData, meta int
 	return nil
 }
 
+// resourceUserStateUpgradeV0 replaces the user name in the ID of the user with its principal id. States without
+// a principal id keep their ID until the user is read.
+func resourceUserStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	host, port, ok := serverFromRawState(rawState)
+	database, _ := rawState[databaseProp].(string)
+	var principalID int64
+	switch v := rawState[principalIdProp].(type) {
+	case float64:
+		principalID = int64(v)
+	case json.Number:
+		principalID, _ = v.Int64()
+	}
+	if !ok || database == "" || principalID == 0 {
+		return rawState, nil
+	}
+	rawState["id"] = fmt.Sprintf("sqlserver://%s:%s/%s/%d", host, port, database, principalID)
+	return rawState, nil
+}
+
 func resourceUserImport(ctx context.Context, data *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	logger := loggerFromMeta(meta, "user", "import")
 	logger.Debug().Msgf("Import %s", data.Id())
@@ -275,14 +592,9 @@ func resourceUserImport(ctx context.Context, data *schema.ResourceData, meta int
 	if err = data.Set(databaseProp, parts[1]); err != nil {
 		return nil, err
 	}
-	if err = data.Set(usernameProp, parts[2]); err != nil {
-		return nil, err
-	}
 
-	data.SetId(getUserID(data))
-
-	database := data.Get(databaseProp).(string)
-	username := data.Get(usernameProp).(string)
+	database := parts[1]
+	username := trimName(parts[2])
 
 	connector, err := getUserConnector(meta, data)
 	if err != nil {
@@ -293,17 +605,35 @@ func resourceUserImport(ctx context.Context, data *schema.ResourceData, meta int
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to read user [%s].[%s] for import", database, username)
 	}
+	// The ID of the resource names the user by its principal id
+	if principalID, perr := strconv.ParseInt(username, 10, 64); login == nil && perr == nil {
+		name, err := connector.GetUserNameByPrincipalID(ctx, database, principalID, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to look up user [%s].[%s] by principal id for import", database, username)
+		}
+		if name != "" {
+			username = name
+			if login, err = connector.GetUser(ctx, database, username); err != nil {
+				return nil, errors.Wrapf(err, "unable to read user [%s].[%s] for import", database, username)
+			}
+		}
+	}
 
 	if login == nil {
 		return nil, errors.Errorf("no user [%s].[%s] found for import", database, username)
 	}
 
+	if err = data.Set(usernameProp, username); err != nil {
+		return nil, err
+	}
+
 	if err = data.Set(authenticationTypeProp, login.AuthType); err != nil {
 		return nil, err
 	}
 	if err = data.Set(principalIdProp, login.PrincipalID); err != nil {
 		return nil, err
 	}
+	data.SetId(getUserID(data))
 	if err = data.Set(defaultSchemaProp, login.DefaultSchema); err != nil {
 		return nil, err
 	}
@@ -313,10 +643,202 @@ func resourceUserImport(ctx context.Context, data *schema.ResourceData, meta int
 	if err = data.Set(rolesProp, login.Roles); err != nil {
 		return nil, err
 	}
+	if err = data.Set(createMissingRolesProp, false); err != nil {
+		return nil, err
+	}
+	if err = data.Set(reassignOwnedToProp, "dbo"); err != nil {
+		return nil, err
+	}
+	if err = data.Set(verifyAccessProp, false); err != nil {
+		return nil, err
+	}
+	if err = data.Set(trackModificationsProp, false); err != nil {
+		return nil, err
+	}
 
 	return []*schema.ResourceData{data}, nil
 }
 
+// ensureRolesExist fails with the names of any roles missing from the database, as they would otherwise
+// be silently skipped. With create_missing_roles the missing roles are created instead.
+func ensureRolesExist(ctx context.Context, connector UserConnector, data *schema.ResourceData, database string, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	existing, err := connector.GetDatabaseRoles(ctx, database)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read roles in database [%s]", database)
+	}
+	var missing []string
+	for _, role := range roles {
+		if !containsFold(existing, role) {
+			missing = append(missing, role)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if !data.Get(createMissingRolesProp).(bool) {
+		return errors.Errorf("roles [%s] do not exist in database [%s]; add depends_on for the resources creating them, or set %s",
+			strings.Join(missing, "], ["), database, createMissingRolesProp)
+	}
+	for _, role := range missing {
+		if err = connector.CreateDatabaseRole(ctx, database, role); err != nil {
+			return errors.Wrapf(err, "unable to create role [%s].[%s]", database, role)
+		}
+	}
+	return nil
+}
+
+// resolveLoginName returns the name of the login as it is on the server, e.g. in a different case on a case
+// insensitive server. If the login is not found, it fails with the names of similar logins, if any. Otherwise,
+// as the provider login may not be allowed to see the login, creating the user is left to report the error.
+func resolveLoginName(ctx context.Context, meta interface{}, data *schema.ResourceData, loginName string) (string, error) {
+	logger := loggerFromMeta(meta, "user", "create")
+	connector, err := getLoginConnector(meta, data)
+	if err != nil {
+		return "", err
+	}
+	login, err := connector.GetLogin(ctx, loginName)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("unable to look up login [%s]", loginName)
+		return loginName, nil
+	}
+	if login != nil {
+		return login.LoginName, nil
+	}
+
+	provider := meta.(model.Provider)
+	c, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return "", err
+	}
+	principals, err := c.(ServerPrincipalsConnector).GetServerPrincipals(ctx, false, false)
+	if err != nil {
+		logger.Warn().Err(err).Msg("unable to list logins")
+		return loginName, nil
+	}
+	names := make([]string, len(principals))
+	for i, principal := range principals {
+		names[i] = principal.Name
+	}
+	if matches := closeNames(loginName, names); len(matches) > 0 {
+		return "", errors.Errorf("login [%s] does not exist; did you mean [%s]?", loginName, strings.Join(matches, "], ["))
+	}
+	return loginName, nil
+}
+
+// defaultUserLoginName returns the name of the SQL login named username, which login_name defaults to, or ""
+// if there is none. The default does not apply on Azure SQL Database, where a user without login_name or
+// password is a contained user from Azure AD, nor to names of Azure AD or Windows principals, such as
+// user@example.com. A failed lookup means no default, as logins may not be visible to the provider login.
+func defaultUserLoginName(ctx context.Context, meta interface{}, data *schema.ResourceData, username string) string {
+	logger := loggerFromMeta(meta, "user", "create")
+	if strings.ContainsAny(username, `@\`) {
+		return ""
+	}
+	connector, err := getLoginConnector(meta, data)
+	if err != nil {
+		return ""
+	}
+	if versionConnector, ok := connector.(ServerVersionConnector); ok {
+		edition, _, err := versionConnector.GetServerVersion(ctx)
+		if err != nil {
+			logger.Debug().Err(err).Msgf("unable to read the engine edition, not defaulting %s of [%s]", loginNameProp, username)
+			return ""
+		}
+		if edition == 5 {
+			return ""
+		}
+	}
+	login, err := connector.GetLogin(ctx, username)
+	if err != nil {
+		logger.Debug().Err(err).Msgf("unable to look up login [%s], not defaulting %s", username, loginNameProp)
+		return ""
+	}
+	if login == nil || login.LoginType != loginTypeSQL {
+		return ""
+	}
+	return login.LoginName
+}
+
+// verifyUserAccess checks with verify_access that the user can access the database and is a member of its roles.
+func verifyUserAccess(ctx context.Context, connector UserConnector, data *schema.ResourceData, user *model.User, timeout time.Duration) error {
+	if !data.Get(verifyAccessProp).(bool) {
+		return nil
+	}
+	database := data.Get(databaseProp).(string)
+	return verifyAccess(ctx, fmt.Sprintf("user [%s].[%s]", database, user.Username), timeout, func() ([]string, error) {
+		return connector.VerifyUserAccess(ctx, database, user.Username, user.Roles)
+	})
+}
+
+// checkExistingUser checks that a user that already exists is the one the configuration would have created,
+// so that it can be adopted. As with logins, the credentials must be verified to match: users with a password
+// are never adopted, as the password hash of a contained user cannot be read to compare it, and EXTERNAL users
+// must have the SID of object_id.
+func checkExistingUser(database string, existing, user *model.User) error {
+	switch {
+	case existing.AuthType != user.AuthType:
+		return existingUserConflict(database, user.Username, authenticationTypeProp, existing.AuthType, user.AuthType)
+	case user.AuthType == "DATABASE":
+		return errors.Errorf("user [%s].[%s] already exists, and its password cannot be compared with %s; import it with terraform import, or drop it, before applying",
+			database, user.Username, passwordProp)
+	case user.AuthType == "EXTERNAL":
+		if err := checkExistingSID(fmt.Sprintf("user [%s].[%s]", database, user.Username), existing.SIDStr, user.ObjectId); err != nil {
+			return err
+		}
+	}
+	switch {
+	case !strings.EqualFold(existing.LoginName, user.LoginName):
+		return existingUserConflict(database, user.Username, loginNameProp, existing.LoginName, user.LoginName)
+	case !strings.EqualFold(existing.DefaultSchema, user.DefaultSchema):
+		return existingUserConflict(database, user.Username, defaultSchemaProp, existing.DefaultSchema, user.DefaultSchema)
+	case user.DefaultLanguage != "" && !strings.EqualFold(existing.DefaultLanguage, user.DefaultLanguage):
+		return existingUserConflict(database, user.Username, defaultLanguageProp, existing.DefaultLanguage, user.DefaultLanguage)
+	}
+	return nil
+}
+
+func existingUserConflict(database, username, prop, existing, configured string) error {
+	return errors.Errorf("user [%s].[%s] already exists with %s [%s] instead of [%s]; import it with terraform import, or drop it, before applying",
+		database, username, prop, existing, configured)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUserPreserved fails if the default schema of the user differs from user, or it lost one of the roles of
+// user, e.g. because a DDL trigger reacted to the rename.
+func checkUserPreserved(ctx context.Context, connector UserConnector, database string, user *model.User) error {
+	actual, err := connector.GetUser(ctx, database, user.Username)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read user [%s].[%s]", database, user.Username)
+	}
+	if actual == nil {
+		return errors.Errorf("user [%s].[%s] not found after rename", database, user.Username)
+	}
+	if !strings.EqualFold(actual.DefaultSchema, user.DefaultSchema) {
+		return errors.Errorf("default schema of user [%s].[%s] is [%s] after rename, expected [%s]", database, user.Username, actual.DefaultSchema, user.DefaultSchema)
+	}
+	memberOf := make(map[string]bool)
+	for _, role := range actual.Roles {
+		memberOf[strings.ToLower(role)] = true
+	}
+	for _, role := range user.Roles {
+		if !memberOf[strings.ToLower(role)] {
+			return errors.Errorf("user [%s].[%s] is no longer a member of role [%s] after rename", database, user.Username, role)
+		}
+	}
+	return nil
+}
+
 func getUserConnector(meta interface{}, data *schema.ResourceData) (UserConnector, error) {
 	provider := meta.(model.Provider)
 	connector, err := provider.GetConnector(serverProp, data)
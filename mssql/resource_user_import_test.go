@@ -20,10 +20,11 @@ func TestAccUser_Local_BasicImport(t *testing.T) {
         ),
       },
       {
-        ResourceName:      "mssql_user.test_import",
-        ImportState:       true,
-        ImportStateVerify: true,
-        ImportStateIdFunc: testAccImportStateId("mssql_user.test_import", false),
+        ResourceName:            "mssql_user.test_import",
+        ImportState:             true,
+        ImportStateVerify:       true,
+        ImportStateVerifyIgnore: []string{"last_applied_sql"},
+        ImportStateIdFunc:       testAccImportStateId("mssql_user.test_import", false),
       },
     },
   })
@@ -1,12 +1,19 @@
 package mssql
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pkg/errors"
 )
 
 func TestAccUser_Local_Instance(t *testing.T) {
@@ -37,6 +44,8 @@ func TestAccUser_Local_Instance(t *testing.T) {
 					resource.TestCheckResourceAttr("mssql_user.instance", "server.0.login.0.password", os.Getenv("MSSQL_PASSWORD")),
 					resource.TestCheckResourceAttr("mssql_user.instance", "server.0.azure_login.#", "0"),
 					resource.TestCheckResourceAttrSet("mssql_user.instance", "principal_id"),
+					resource.TestCheckResourceAttrSet("mssql_user.instance", "server_name"),
+					resource.TestCheckResourceAttrSet("mssql_user.instance", "modify_date"),
 					resource.TestCheckNoResourceAttr("mssql_user.instance", "password"),
 				),
 			},
@@ -44,6 +53,42 @@ func TestAccUser_Local_Instance(t *testing.T) {
 	})
 }
 
+func TestAccUser_Local_Instance_DefaultLoginName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckUserDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckUser(t, "instance", "login", map[string]interface{}{"username": "user_default", "login_name": "user_default", "login_password": "valueIsH8kd$¡", "omit_login_name": true}),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckUserExists("mssql_user.instance", Check{"login_name", "==", "user_default"}),
+					testAccCheckDatabaseUserWorks("mssql_user.instance", "user_default", "valueIsH8kd$¡"),
+					resource.TestCheckResourceAttr("mssql_user.instance", "username", "user_default"),
+					resource.TestCheckResourceAttr("mssql_user.instance", "login_name", "user_default"),
+					resource.TestCheckResourceAttr("mssql_user.instance", "authentication_type", "INSTANCE"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_Local_MissingDatabase(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckUserDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckUser(t, "missing", "login", map[string]interface{}{"database": "missingdb", "username": "missing", "password": "valueIsH8kd$¡"}),
+				ExpectError: regexp.MustCompile(`database \[missingdb\] does not exist`),
+			},
+		},
+	})
+}
+
 func TestAccMultipleUsers_Local_Instance(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -364,6 +409,195 @@ func TestAccUser_Local_Update_Roles(t *testing.T) {
 	})
 }
 
+func TestAccUser_Local_MissingRoles(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckLoginDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckUser(t, "roles", "login", map[string]interface{}{"username": "test_roles", "login_name": "user_roles", "login_password": "valueIsH8kd$¡", "roles": "[\"db_owner\",\"missing_role\"]"}),
+				ExpectError: regexp.MustCompile(`roles \[missing_role\] do not exist in database \[master\]`),
+			},
+			{
+				Config: testAccCheckUser(t, "roles", "login", map[string]interface{}{"username": "test_roles", "login_name": "user_roles", "login_password": "valueIsH8kd$¡", "roles": "[\"db_owner\",\"missing_role\"]", "create_missing_roles": "true"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_user.roles", "roles.#", "2"),
+					testAccCheckUserExists("mssql_user.roles", Check{"roles", "==", []string{"db_owner", "missing_role"}}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_Local_VerifyAccess(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckUserDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckUser(t, "verify", "login", map[string]interface{}{"username": "test_verify", "login_name": "user_verify", "login_password": "valueIsH8kd$¡", "roles": "[\"db_datareader\"]", "verify_access": "true"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_user.verify", "verify_access", "true"),
+					testAccCheckUserExists("mssql_user.verify", Check{"roles", "==", []string{"db_datareader"}}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_Local_RenamedExternally(t *testing.T) {
+	var principalID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckUserDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckUser(t, "rename", "login", map[string]interface{}{"username": "test_rename", "login_name": "user_rename", "login_password": "valueIsH8kd$¡"}),
+				Check: func(state *terraform.State) error {
+					principalID = state.RootModule().Resources["mssql_user.rename"].Primary.Attributes["principal_id"]
+					if id := state.RootModule().Resources["mssql_user.rename"].Primary.ID; !strings.HasSuffix(id, "/"+principalID) {
+						return fmt.Errorf("expected ID [%s] to end with principal_id %s", id, principalID)
+					}
+					return nil
+				},
+			},
+			{
+				PreConfig: func() {
+					connector, err := getTestConnector(map[string]string{
+						"server.0.host":             "localhost",
+						"server.0.port":             DefaultPort,
+						"server.0.login.0.username": os.Getenv("MSSQL_USERNAME"),
+						"server.0.login.0.password": os.Getenv("MSSQL_PASSWORD"),
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err = connector.(testConnector).c.(RawExecConnector).ExecuteStatement(context.Background(), "master", "ALTER USER [test_rename] WITH NAME = [test_renamed]"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccCheckUser(t, "rename", "login", map[string]interface{}{"username": "test_rename", "login_name": "user_rename", "login_password": "valueIsH8kd$¡"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_user.rename", "username", "test_rename"),
+					func(state *terraform.State) error {
+						if actual := state.RootModule().Resources["mssql_user.rename"].Primary.Attributes["principal_id"]; actual != principalID {
+							return fmt.Errorf("expected user to be renamed back with principal_id %s, but got %s", principalID, actual)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_Local_Rename(t *testing.T) {
+	var principalID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckUserDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckUser(t, "rename", "login", map[string]interface{}{"username": "test_rename", "login_name": "user_rename", "login_password": "valueIsH8kd$¡", "default_schema": "sys", "roles": "[\"db_datareader\",\"db_datawriter\"]"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("mssql_user.rename", "last_applied_sql", regexp.MustCompile(`^CREATE USER \[test_rename\] FOR LOGIN \[user_rename\] WITH DEFAULT_SCHEMA = \[sys\]\n`)),
+					resource.TestMatchResourceAttr("mssql_user.rename", "last_applied_sql", regexp.MustCompile(`\nALTER ROLE \[db_datareader\] ADD MEMBER \[test_rename\]`)),
+					resource.TestMatchResourceAttr("mssql_user.rename", "last_applied_sql", regexp.MustCompile(`\nALTER ROLE \[db_datawriter\] ADD MEMBER \[test_rename\]`)),
+					func(state *terraform.State) error {
+						principalID = state.RootModule().Resources["mssql_user.rename"].Primary.Attributes["principal_id"]
+						if id := state.RootModule().Resources["mssql_user.rename"].Primary.ID; !strings.HasSuffix(id, "/"+principalID) {
+							return fmt.Errorf("expected ID [%s] to end with principal_id %s", id, principalID)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccCheckUser(t, "rename", "login", map[string]interface{}{"username": "test_renamed", "login_name": "user_rename", "login_password": "valueIsH8kd$¡", "default_schema": "sys", "roles": "[\"db_datareader\",\"db_datawriter\"]"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_user.rename", "username", "test_renamed"),
+					resource.TestCheckResourceAttr("mssql_user.rename", "last_applied_sql", "ALTER USER [test_rename] WITH NAME = [test_renamed]"),
+					testAccCheckUserExists("mssql_user.rename", Check{"default_schema", "==", "sys"}, Check{"roles", "==", []string{"db_datareader", "db_datawriter"}}),
+					testAccCheckDatabaseUserWorks("mssql_user.rename", "user_rename", "valueIsH8kd$¡"),
+					func(state *terraform.State) error {
+						if actual := state.RootModule().Resources["mssql_user.rename"].Primary.Attributes["principal_id"]; actual != principalID {
+							return fmt.Errorf("expected user to be renamed in place with principal_id %s, but got %s", principalID, actual)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_Local_ReassignOwned(t *testing.T) {
+	ownedSchema := `
+           resource "mssql_raw_exec" "owned_schema" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             create_sql = "IF SCHEMA_ID('reassign_owned') IS NULL EXEC('CREATE SCHEMA [reassign_owned]'); ALTER AUTHORIZATION ON SCHEMA::[reassign_owned] TO [${mssql_user.owner.username}]"
+           }`
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckUserDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckUser(t, "owner", "login", map[string]interface{}{"username": "test_owner", "login_name": "user_owner", "login_password": "valueIsH8kd$¡", "roles": "[\"db_datareader\"]"}) + ownedSchema,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_user.owner", "reassign_owned_to", "dbo"),
+					resource.TestMatchResourceAttr("mssql_user.owner", "last_applied_sql", regexp.MustCompile(`\nALTER ROLE \[db_datareader\] ADD MEMBER \[test_owner\]$`)),
+				),
+			},
+			{
+				Config: testAccCheckUser(t, "owner", "login", map[string]interface{}{"username": "test_owner", "login_name": "user_owner", "login_password": "valueIsH8kd$¡", "roles": "[\"db_datawriter\"]"}) + ownedSchema,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("mssql_user.owner", "last_applied_sql", regexp.MustCompile(`^ALTER USER \[test_owner\] WITH DEFAULT_SCHEMA = \[dbo\]`)),
+					resource.TestMatchResourceAttr("mssql_user.owner", "last_applied_sql", regexp.MustCompile(`\nALTER ROLE \[db_datareader\] DROP MEMBER \[test_owner\]\nALTER ROLE \[db_datawriter\] ADD MEMBER \[test_owner\]$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUser_Local_DeleteWithGrants(t *testing.T) {
+	database := testAccLocalDatabase(t, "user_grants")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		CheckDestroy:      func(state *terraform.State) error { return testAccCheckUserDestroy(state) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckUser(t, "grantor", "login", map[string]interface{}{"database": database, "username": "test_grantor", "login_name": "user_grantor", "login_password": "valueIsH8kd$¡", "roles": "[\"db_datareader\"]"}) + `
+           resource "mssql_raw_exec" "grants" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database   = "` + database + `"
+             create_sql = "GRANT SELECT ON SCHEMA::[dbo] TO [${mssql_user.grantor.username}] WITH GRANT OPTION; GRANT SELECT ON SCHEMA::[dbo] TO [public] AS [${mssql_user.grantor.username}]"
+           }`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_user.grantor", "roles.#", "1"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccUser_Azure_Update_DefaultSchema(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -472,10 +706,12 @@ func testAccCheckUser(t *testing.T, name string, login string, data map[string]i
              {{ with .database }}database = "{{ . }}"{{ end }}
              username = "{{ .username }}"
              {{ with .password }}password = "{{ . }}"{{ end }}
-             {{ with .login_name }}login_name = "{{ . }}"{{ end }}
+             {{ if .omit_login_name }}depends_on = [mssql_login.{{ .name }}]{{ else }}{{ with .login_name }}login_name = "{{ . }}"{{ end }}{{ end }}
              {{ with .default_schema }}default_schema = "{{ . }}"{{ end }}
              {{ with .default_language }}default_language = "{{ . }}"{{ end }}
              {{ with .roles }}roles = {{ . }}{{ end }}
+             {{ with .create_missing_roles }}create_missing_roles = {{ . }}{{ end }}
+             {{ with .verify_access }}verify_access = {{ . }}{{ end }}
            }`
 	data["name"] = name
 	data["login"] = login
@@ -518,6 +754,7 @@ func testAccCheckMultipleUsers(t *testing.T, name string, login string, data map
              {{ with .default_schema }}default_schema = "{{ . }}"{{ end }}
              {{ with .default_language }}default_language = "{{ . }}"{{ end }}
              {{ with .roles }}roles = {{ . }}{{ end }}
+             {{ with .create_missing_roles }}create_missing_roles = {{ . }}{{ end }}
            }`
 	data["name"] = name
 	data["login"] = login
@@ -717,3 +954,119 @@ func getMultipleUsersExistAccCheck(count int) []resource.TestCheckFunc {
 	}
 	return checkFuncs
 }
+
+func TestUserStateUpgradeV0(t *testing.T) {
+	testCases := []struct {
+		name        string
+		principalID interface{}
+		expected    string
+	}{
+		{"principal id", float64(5), "sqlserver://localhost:1433/master/5"},
+		{"json number", json.Number("5"), "sqlserver://localhost:1433/master/5"},
+		{"no principal id", nil, "mssql://localhost/master/test"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rawState := map[string]interface{}{
+				"id":           "mssql://localhost/master/test",
+				"server":       []interface{}{map[string]interface{}{"host": "localhost", "port": "1433"}},
+				"database":     "master",
+				"username":     "test",
+				"principal_id": tc.principalID,
+				"roles":        []interface{}{"db_owner"},
+			}
+			upgraded, err := resourceUserStateUpgradeV0(context.Background(), rawState, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if upgraded["id"] != tc.expected {
+				t.Errorf("expected ID [%s], got [%s]", tc.expected, upgraded["id"])
+			}
+			if roles := upgraded["roles"].([]interface{}); len(roles) != 1 || roles[0] != "db_owner" {
+				t.Errorf("expected roles to be kept, got %v", roles)
+			}
+		})
+	}
+}
+
+func TestCheckExistingUser(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		existing model.User
+		user     model.User
+		err      string
+	}{
+		{"same instance", model.User{AuthType: "INSTANCE", LoginName: "Login", DefaultSchema: "dbo"}, model.User{AuthType: "INSTANCE", LoginName: "login", DefaultSchema: "dbo"}, ""},
+		{"same external", model.User{AuthType: "EXTERNAL", SIDStr: testObjectSID, DefaultSchema: "dbo", DefaultLanguage: "us_english"}, model.User{AuthType: "EXTERNAL", ObjectId: testObjectId, DefaultSchema: "dbo"}, ""},
+		{"external without object id", model.User{AuthType: "EXTERNAL", SIDStr: testObjectSID, DefaultSchema: "dbo"}, model.User{AuthType: "EXTERNAL", DefaultSchema: "dbo"}, "without object_id it cannot be verified"},
+		{"other external", model.User{AuthType: "EXTERNAL", SIDStr: "0x01", DefaultSchema: "dbo"}, model.User{AuthType: "EXTERNAL", ObjectId: testObjectId, DefaultSchema: "dbo"}, "SID [0x01] instead of [" + testObjectSID + "]"},
+		{"other authentication", model.User{AuthType: "EXTERNAL", DefaultSchema: "dbo"}, model.User{AuthType: "INSTANCE", LoginName: "login", DefaultSchema: "dbo"}, "authentication_type [EXTERNAL] instead of [INSTANCE]"},
+		{"password", model.User{AuthType: "DATABASE", DefaultSchema: "dbo"}, model.User{AuthType: "DATABASE", Password: "valueIsH8kd$¡", DefaultSchema: "dbo"}, "password cannot be compared"},
+		{"other login", model.User{AuthType: "INSTANCE", LoginName: "other", DefaultSchema: "dbo"}, model.User{AuthType: "INSTANCE", LoginName: "login", DefaultSchema: "dbo"}, "login_name [other] instead of [login]"},
+		{"other default schema", model.User{AuthType: "INSTANCE", LoginName: "login", DefaultSchema: "app"}, model.User{AuthType: "INSTANCE", LoginName: "login", DefaultSchema: "dbo"}, "default_schema [app] instead of [dbo]"},
+		{"other default language", model.User{AuthType: "EXTERNAL", SIDStr: testObjectSID, DefaultSchema: "dbo", DefaultLanguage: "us_english"}, model.User{AuthType: "EXTERNAL", ObjectId: testObjectId, DefaultSchema: "dbo", DefaultLanguage: "Deutsch"}, "default_language [us_english] instead of [Deutsch]"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.existing.Username, tc.user.Username = "user", "user"
+			err := checkExistingUser("app", &tc.existing, &tc.user)
+			if tc.err == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+				t.Errorf("expected error containing [%s], got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestDefaultUserLoginName(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		username string
+		login    *model.Login
+		err      error
+		expected string
+		queries  int
+	}{
+		{"sql login", "app", &model.Login{LoginName: "App", LoginType: loginTypeSQL}, nil, "App", 1},
+		{"windows login", "app", &model.Login{LoginName: "app", LoginType: loginTypeWindows}, nil, "", 1},
+		{"no login", "app", nil, nil, "", 1},
+		{"lookup failed", "app", nil, errors.New("login not visible"), "", 1},
+		{"azure ad name", "app@example.com", &model.Login{LoginName: "app@example.com", LoginType: loginTypeSQL}, nil, "", 0},
+		{"windows name", `DOMAIN\app`, &model.Login{LoginName: `DOMAIN\app`, LoginType: loginTypeSQL}, nil, "", 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			connector := &countingLoginConnector{login: tc.login, err: tc.err}
+			data := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{"username": tc.username})
+			loginName := defaultUserLoginName(context.Background(), countingProvider{connector: connector}, data, tc.username)
+			if loginName != tc.expected {
+				t.Errorf("expected login name [%s], got [%s]", tc.expected, loginName)
+			}
+			if connector.queries != tc.queries {
+				t.Errorf("expected %d queries, got %d", tc.queries, connector.queries)
+			}
+		})
+	}
+}
+
+func TestCloseNames(t *testing.T) {
+	names := []string{"app_reader", "App_Writer", "sa", "reporting"}
+	for _, tc := range []struct {
+		name     string
+		expected []string
+	}{
+		{"app_writer", []string{"App_Writer"}},
+		{" app_reader ", []string{"app_reader"}},
+		{"app_readr", []string{"app_reader"}},
+		{"app_reade", []string{"app_reader"}},
+		{"app_", nil},
+		{"monitoring", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			matches := closeNames(tc.name, names)
+			if strings.Join(matches, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("expected %v, got %v", tc.expected, matches)
+			}
+		})
+	}
+}
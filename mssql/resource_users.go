@@ -0,0 +1,295 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+const userProp = "user"
+const removeUnlistedProp = "remove_unlisted"
+
+type UsersConnector interface {
+	GetUsers(ctx context.Context, database string) ([]*model.User, error)
+	SetUsers(ctx context.Context, database string, current, desired []*model.User, dropUnlisted bool) ([]string, error)
+	DropUsers(ctx context.Context, database string, usernames []string) error
+}
+
+func resourceUsers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUsersCreate,
+		ReadContext:   resourceUsersRead,
+		UpdateContext: resourceUsersUpdate,
+		DeleteContext: resourceUsersDelete,
+		Schema: map[string]*schema.Schema{
+			serverProp: {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: getServerSchema(serverProp),
+				},
+			},
+			databaseProp: {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			userProp: {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						usernameProp: {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						loginNameProp: {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						defaultSchemaProp: {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  defaultSchemaPropDefault,
+						},
+						rolesProp: {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			removeUnlistedProp: {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			lastAppliedSqlProp: {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+			seen := make(map[string]bool)
+			for _, u := range diff.Get(userProp).(*schema.Set).List() {
+				username := strings.ToLower(u.(map[string]interface{})[usernameProp].(string))
+				if seen[username] {
+					return errors.Errorf("user [%s] is listed more than once", username)
+				}
+				seen[username] = true
+			}
+			return nil
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: defaultTimeout,
+		},
+	}
+}
+
+func resourceUsersCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "users", "create")
+	logger.Debug().Msgf("Create %s", getUsersID(data))
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseExists(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := checkDatabaseWritable(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setUsers(ctx, meta, data); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(getUsersID(data))
+
+	logger.Info().Msgf("created users in database [%s]", database)
+
+	return resourceUsersRead(ctx, data, meta)
+}
+
+func resourceUsersRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "users", "read")
+	logger.Debug().Msgf("Read %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getUsersConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	users, err := connector.GetUsers(ctx, database)
+	if err != nil {
+		return readFailed(ctx, meta, data, errors.Wrapf(err, "unable to read users of database [%s]", database))
+	}
+
+	// Without remove_unlisted, users that are not listed are not managed, so they are left out of the state
+	configured := make(map[string]map[string]interface{})
+	for _, u := range data.Get(userProp).(*schema.Set).List() {
+		u := u.(map[string]interface{})
+		configured[strings.ToLower(u[usernameProp].(string))] = u
+	}
+	removeUnlisted := data.Get(removeUnlistedProp).(bool)
+	result := make([]map[string]interface{}, 0, len(users))
+	for _, user := range users {
+		u, ok := configured[strings.ToLower(user.Username)]
+		if !ok && !removeUnlisted {
+			continue
+		}
+		username, loginName := user.Username, user.LoginName
+		if ok {
+			// Keep the spelling of the configuration, and an omitted login_name that defaults to username
+			username = u[usernameProp].(string)
+			if u[loginNameProp].(string) == "" && strings.EqualFold(loginName, user.Username) {
+				loginName = ""
+			}
+		}
+		roles := user.Roles
+		if ok {
+			roles = keepConfiguredCase(user.Roles, toStringSlice(u[rolesProp].(*schema.Set).List()))
+		}
+		result = append(result, map[string]interface{}{
+			usernameProp:      username,
+			loginNameProp:     loginName,
+			defaultSchemaProp: user.DefaultSchema,
+			rolesProp:         roles,
+		})
+	}
+	if err = data.Set(userProp, result); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceUsersUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "users", "update")
+	logger.Debug().Msgf("Update %s", data.Id())
+
+	if data.HasChanges(userProp, removeUnlistedProp) {
+		if err := checkDatabaseWritable(ctx, meta, data, data.Get(databaseProp).(string)); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := setUsers(ctx, meta, data); err != nil {
+			return diag.FromErr(err)
+		}
+		logger.Info().Msgf("updated users in database [%s]", data.Get(databaseProp).(string))
+	}
+
+	return resourceUsersRead(ctx, data, meta)
+}
+
+func resourceUsersDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	logger := loggerFromMeta(meta, "users", "delete")
+	logger.Debug().Msgf("Delete %s", data.Id())
+
+	database := data.Get(databaseProp).(string)
+
+	if err := checkDatabaseWritable(ctx, meta, data, database); err != nil {
+		return diag.FromErr(err)
+	}
+
+	connector, err := getUsersConnector(meta, data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var usernames []string
+	for _, u := range data.Get(userProp).(*schema.Set).List() {
+		usernames = append(usernames, u.(map[string]interface{})[usernameProp].(string))
+	}
+	sort.Strings(usernames)
+	if err = connector.DropUsers(ctx, database, usernames); err != nil {
+		return diag.FromErr(errors.Wrapf(err, "unable to drop users of database [%s]", database))
+	}
+
+	logger.Info().Msgf("dropped %d users in database [%s]", len(usernames), database)
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but it is added here for explicitness.
+	data.SetId("")
+
+	return nil
+}
+
+// setUsers applies the listed users to the database in one batch, and records the executed statements.
+func setUsers(ctx context.Context, meta interface{}, data *schema.ResourceData) error {
+	database := data.Get(databaseProp).(string)
+
+	connector, err := getUsersConnector(meta, data)
+	if err != nil {
+		return err
+	}
+
+	current, err := connector.GetUsers(ctx, database)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read users of database [%s]", database)
+	}
+
+	var desired []*model.User
+	for _, u := range data.Get(userProp).(*schema.Set).List() {
+		u := u.(map[string]interface{})
+		user := &model.User{
+			Username:      u[usernameProp].(string),
+			LoginName:     u[loginNameProp].(string),
+			AuthType:      "INSTANCE",
+			DefaultSchema: u[defaultSchemaProp].(string),
+			Roles:         toStringSlice(u[rolesProp].(*schema.Set).List()),
+		}
+		if user.LoginName == "" {
+			user.LoginName = user.Username
+		}
+		sort.Strings(user.Roles)
+		desired = append(desired, user)
+	}
+	sort.Slice(desired, func(i, j int) bool { return desired[i].Username < desired[j].Username })
+
+	applied, err := connector.SetUsers(ctx, database, current, desired, data.Get(removeUnlistedProp).(bool))
+	if err != nil {
+		return errors.Wrapf(err, "unable to set users of database [%s]", database)
+	}
+	return data.Set(lastAppliedSqlProp, strings.Join(applied, "\n"))
+}
+
+// keepConfiguredCase returns values, with the spelling of configured for those that only differ in case.
+func keepConfiguredCase(values, configured []string) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = value
+		for _, c := range configured {
+			if strings.EqualFold(c, value) {
+				result[i] = c
+				break
+			}
+		}
+	}
+	return result
+}
+
+func getUsersID(data *schema.ResourceData) string {
+	host := data.Get(serverProp + ".0.host").(string)
+	port := data.Get(serverProp + ".0.port").(string)
+	database := data.Get(databaseProp).(string)
+	return fmt.Sprintf("sqlserver://%s:%s/%s/users", host, port, database)
+}
+
+func getUsersConnector(meta interface{}, data *schema.ResourceData) (UsersConnector, error) {
+	provider := meta.(model.Provider)
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return nil, err
+	}
+	return connector.(UsersConnector), nil
+}
@@ -0,0 +1,78 @@
+package mssql
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUsers_Local_Basic(t *testing.T) {
+	database := testAccLocalDatabase(t, "users_test")
+	prefix := "users_" + acctest.RandString(8)
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		IsUnitTest:        runLocalAccTests,
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckUsers(t, "test", map[string]interface{}{"database": database, "prefix": prefix, "count": 3, "roles": `["db_datareader"]`}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_users.test", "user.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs("mssql_users.test", "user.*", map[string]string{
+						"username":       prefix + "_0",
+						"login_name":     "",
+						"default_schema": "dbo",
+						"roles.#":        "1",
+						"roles.0":        "db_datareader",
+					}),
+				),
+			},
+			{
+				Config: testAccCheckUsers(t, "test", map[string]interface{}{"database": database, "prefix": prefix, "count": 2, "roles": `["db_datawriter"]`}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mssql_users.test", "user.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("mssql_users.test", "user.*", map[string]string{
+						"username": prefix + "_1",
+						"roles.#":  "1",
+						"roles.0":  "db_datawriter",
+					}),
+					resource.TestMatchResourceAttr("mssql_users.test", "last_applied_sql", regexp.MustCompile(`DROP USER \[`+prefix+`_2\]`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckUsers(t *testing.T, name string, data map[string]interface{}) string {
+	text := `resource "mssql_login" "{{ .name }}" {
+             count = 3
+             server {
+               host = "localhost"
+               login {}
+             }
+             login_name = "{{ .prefix }}_${count.index}"
+             password   = "valueIsH8kd$¡"
+           }
+           resource "mssql_users" "{{ .name }}" {
+             server {
+               host = "localhost"
+               login {}
+             }
+             database = "{{ .database }}"
+             dynamic "user" {
+               for_each = slice(mssql_login.{{ .name }}[*].login_name, 0, {{ .count }})
+               content {
+                 username = user.value
+                 roles    = {{ .roles }}
+               }
+             }
+           }`
+	data["name"] = name
+	res, err := templateToString(name, text, data)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	return res
+}
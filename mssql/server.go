@@ -1,18 +1,204 @@
 package mssql
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
 
+	"github.com/betr-io/terraform-provider-mssql/mssql/model"
+	"github.com/betr-io/terraform-provider-mssql/sql"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const DefaultPort = "1433"
 
+var thumbprintRegexp = regexp.MustCompile(`^([0-9A-Fa-f]{40}|[0-9A-Fa-f]{64})$`)
+
+const (
+	azureEnvironmentPublic       = "public"
+	azureEnvironmentUSGovernment = "usgovernment"
+	azureEnvironmentChina        = "china"
+)
+
+const (
+	columnEncryptionEnabled  = "Enabled"
+	columnEncryptionDisabled = "Disabled"
+)
+
+type ServerConnector interface {
+	GetServerName(ctx context.Context) (string, error)
+}
+
+type ServerVersionConnector interface {
+	GetServerVersion(ctx context.Context) (int, int, error)
+}
+
 func getServerSchema(prefix string) map[string]*schema.Schema {
+	if len(prefix) > 0 {
+		prefix = prefix + ".0."
+	}
+	var LoginMethods = []string{
+		prefix + "login",
+		prefix + "azure_login",
+		prefix + "azuread_default_chain_auth",
+		prefix + "azuread_managed_identity_auth",
+		prefix + "azuread_interactive_auth",
+	}
+	return map[string]*schema.Schema{
+		"host": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+			DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+				return strings.EqualFold(old, new)
+			},
+		},
+		"port": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+			Default:  DefaultPort,
+		},
+		"host_name_in_certificate": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"server_certificate_thumbprint": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ValidateFunc:  validation.StringMatch(thumbprintRegexp, "must be a SHA-1 or SHA-256 thumbprint as hex, without separators"),
+			ConflictsWith: []string{prefix + "azuread_default_chain_auth", prefix + "azuread_managed_identity_auth"},
+		},
+		"tls_min_version": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"1.0", "1.1", "1.2", "1.3"}, false),
+		},
+		"column_encryption": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      columnEncryptionDisabled,
+			ValidateFunc: validation.StringInSlice([]string{columnEncryptionEnabled, columnEncryptionDisabled}, false),
+			DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+				// State written before the attribute existed has no value; treat it as disabled.
+				return old == "" && new == columnEncryptionDisabled
+			},
+		},
+		"login": {
+			Type:         schema.TypeList,
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: LoginMethods,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"username": {
+						Type:        schema.TypeString,
+						Required:    true,
+						DefaultFunc: requiredEnvDefaultFunc("login", "username", "MSSQL_USERNAME"),
+					},
+					"password": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Sensitive:   true,
+						DefaultFunc: requiredEnvDefaultFunc("login", "password", "MSSQL_PASSWORD"),
+					},
+				},
+			},
+		},
+		"azure_login": {
+			Type:         schema.TypeList,
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: LoginMethods,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"tenant_id": {
+						Type:        schema.TypeString,
+						Required:    true,
+						DefaultFunc: requiredEnvDefaultFunc("azure_login", "tenant_id", "MSSQL_TENANT_ID"),
+					},
+					"client_id": {
+						Type:        schema.TypeString,
+						Required:    true,
+						DefaultFunc: requiredEnvDefaultFunc("azure_login", "client_id", "MSSQL_CLIENT_ID"),
+					},
+					"client_secret": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Sensitive:   true,
+						DefaultFunc: requiredEnvDefaultFunc("azure_login", "client_secret", "MSSQL_CLIENT_SECRET"),
+					},
+					"environment": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						DefaultFunc:  schema.EnvDefaultFunc("MSSQL_AZURE_ENVIRONMENT", azureEnvironmentPublic),
+						ValidateFunc: validation.StringInSlice([]string{azureEnvironmentPublic, azureEnvironmentUSGovernment, azureEnvironmentChina}, false),
+					},
+				},
+			},
+		},
+		"azuread_default_chain_auth": {
+			Type:         schema.TypeList,
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: LoginMethods,
+			Elem:         &schema.Resource{},
+		},
+		"azuread_managed_identity_auth": {
+			Type:         schema.TypeList,
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: LoginMethods,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"user_id": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"azuread_interactive_auth": {
+			Type:         schema.TypeList,
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: LoginMethods,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"tenant_id": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"client_id": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"device_code": {
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
+					"environment": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						DefaultFunc:  schema.EnvDefaultFunc("MSSQL_AZURE_ENVIRONMENT", azureEnvironmentPublic),
+						ValidateFunc: validation.StringInSlice([]string{azureEnvironmentPublic, azureEnvironmentUSGovernment, azureEnvironmentChina}, false),
+					},
+				},
+			},
+		},
+	}
+}
+
+// getServerSchemaV0 is the server block as released before the resources had schema versions, for the schemas
+// of state upgraders. It must not be changed along with getServerSchema.
+func getServerSchemaV0(prefix string) map[string]*schema.Schema {
 	if len(prefix) > 0 {
 		prefix = prefix + ".0."
 	}
@@ -130,8 +316,14 @@ func serverFromId(id string) ([]map[string]interface{}, *url.URL, error) {
 
 	values := u.Query()
 
-	login, loginInValues := getLogin(values)
-	azureLogin, azureInValues := getAzureLogin(values)
+	login, loginInValues, err := getLogin(values)
+	if err != nil {
+		return nil, nil, err
+	}
+	azureLogin, azureInValues, err := getAzureLogin(values)
+	if err != nil {
+		return nil, nil, err
+	}
 	if login == nil && azureLogin == nil {
 		return nil, nil, errors.New("neither login nor azure login specified")
 	}
@@ -152,14 +344,15 @@ func serverFromId(id string) ([]map[string]interface{}, *url.URL, error) {
 	}
 
 	return []map[string]interface{}{{
-		"host":        host,
-		"port":        port,
-		"login":       login,
-		"azure_login": azureLogin,
+		"host":              host,
+		"port":              port,
+		"login":             login,
+		"azure_login":       azureLogin,
+		"column_encryption": columnEncryptionDisabled,
 	}}, u, nil
 }
 
-func getLogin(values url.Values) ([]map[string]interface{}, bool) {
+func getLogin(values url.Values) ([]map[string]interface{}, bool, error) {
 	var inValues bool
 
 	username := values.Get("username")
@@ -171,22 +364,25 @@ func getLogin(values url.Values) ([]map[string]interface{}, bool) {
 
 	password := values.Get("password")
 	if password == "" {
-		password = os.Getenv("MSSQL_PASSWORD")
+		var err error
+		if password, err = envOrFile("MSSQL_PASSWORD"); err != nil {
+			return nil, false, err
+		}
 	} else {
 		inValues = true
 	}
 
 	if username == "" || password == "" {
-		return nil, false
+		return nil, false, nil
 	}
 
 	return []map[string]interface{}{{
 		"username": username,
 		"password": password,
-	}}, inValues
+	}}, inValues, nil
 }
 
-func getAzureLogin(values url.Values) ([]map[string]interface{}, bool) {
+func getAzureLogin(values url.Values) ([]map[string]interface{}, bool, error) {
 	var inValues bool
 
 	tenantId := values.Get("tenant_id")
@@ -205,18 +401,182 @@ func getAzureLogin(values url.Values) ([]map[string]interface{}, bool) {
 
 	clientSecret := values.Get("client_secret")
 	if clientSecret == "" {
-		clientSecret = os.Getenv("MSSQL_CLIENT_SECRET")
+		var err error
+		if clientSecret, err = envOrFile("MSSQL_CLIENT_SECRET"); err != nil {
+			return nil, false, err
+		}
 	} else {
 		inValues = true
 	}
 
 	if tenantId == "" || clientId == "" || clientSecret == "" {
-		return nil, false
+		return nil, false, nil
+	}
+
+	environment := values.Get("environment")
+	if environment == "" {
+		environment = os.Getenv("MSSQL_AZURE_ENVIRONMENT")
+	}
+	if environment == "" {
+		environment = azureEnvironmentPublic
 	}
 
 	return []map[string]interface{}{{
 		"tenant_id":     tenantId,
 		"client_id":     clientId,
 		"client_secret": clientSecret,
-	}}, inValues
+		"environment":   environment,
+	}}, inValues, nil
+}
+
+// loginMethodEnvVars are the environment variables that the attributes of each login block default to.
+var loginMethodEnvVars = []struct {
+	block string
+	names []string
+}{
+	{"login", []string{"MSSQL_USERNAME", "MSSQL_PASSWORD"}},
+	{"azure_login", []string{"MSSQL_TENANT_ID", "MSSQL_CLIENT_ID", "MSSQL_CLIENT_SECRET"}},
+}
+
+// secretEnvVars can also be read from the file named by the variable with a _FILE suffix.
+var secretEnvVars = map[string]bool{
+	"MSSQL_PASSWORD":      true,
+	"MSSQL_CLIENT_SECRET": true,
+}
+
+// requiredEnvDefaultFunc is schema.EnvDefaultFunc for the required attributes of the login blocks. When the
+// environment variable is not set, the error names the environment variables of the block that are missing,
+// and the login methods whose environment variables are all set, instead of the generic error for a missing
+// required argument.
+func requiredEnvDefaultFunc(block, attr, name string) schema.SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		value := os.Getenv(name)
+		if secretEnvVars[name] {
+			var err error
+			if value, err = envOrFile(name); err != nil {
+				return nil, err
+			}
+		}
+		if value != "" {
+			return value, nil
+		}
+		return nil, missingLoginEnvError(block, attr, name)
+	}
+}
+
+func missingLoginEnvError(block, attr, name string) error {
+	source := name
+	if secretEnvVars[name] {
+		source = name + " or " + name + "_FILE"
+	}
+	msg := fmt.Sprintf("%s is not set in the %s block, and neither is %s.", attr, block, source)
+	for _, method := range loginMethodEnvVars {
+		var missing []string
+		for _, n := range method.names {
+			if !envIsSet(n) {
+				missing = append(missing, n)
+			}
+		}
+		switch {
+		case method.block == block && len(missing) > 0:
+			msg += fmt.Sprintf(" Of the environment variables of the %s block, %s not set.", block, enumerate(missing))
+		case method.block != block && len(missing) == 0:
+			msg += fmt.Sprintf(" The environment variables of the %s block are all set, so a %s block may have been meant instead.", method.block, method.block)
+		}
+	}
+	return errors.New(msg)
+}
+
+func envIsSet(name string) bool {
+	return os.Getenv(name) != "" || (secretEnvVars[name] && os.Getenv(name+"_FILE") != "")
+}
+
+// enumerate joins names as a sentence, e.g. "A and B are".
+func enumerate(names []string) string {
+	if len(names) == 1 {
+		return names[0] + " is"
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1] + " are"
+}
+
+// envOrFile returns the environment variable name if it is set, and otherwise the content of the file named
+// by name_FILE without trailing newlines. It returns an empty string if neither is set.
+func envOrFile(name string) (string, error) {
+	if value := os.Getenv(name); value != "" {
+		return value, nil
+	}
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s_FILE: %w", name, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// getPlannedServerVersion returns the engine edition and major version of the server of a resource during plan,
+// to reject configurations the server does not support before apply. ok is false if the server block is not
+// known until apply, or the server cannot be reached within the default timeout, e.g. because the same apply
+// creates it, in which case the configuration is only checked by apply.
+func getPlannedServerVersion(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) (edition, majorVersion int, ok bool) {
+	provider, isProvider := meta.(model.Provider)
+	if !isProvider || !diff.GetRawConfig().GetAttr(serverProp).IsWhollyKnown() {
+		return 0, 0, false
+	}
+	data := (&schema.Resource{Schema: map[string]*schema.Schema{
+		serverProp: {
+			Type:     schema.TypeList,
+			MaxItems: 1,
+			Required: true,
+			Elem: &schema.Resource{
+				Schema: getServerSchema(serverProp),
+			},
+		},
+	}}).Data(nil)
+	if err := data.Set(serverProp, diff.Get(serverProp)); err != nil {
+		return 0, 0, false
+	}
+	connector, err := provider.GetConnector(serverProp, data)
+	if err != nil {
+		return 0, 0, false
+	}
+	if c, isSQL := connector.(*sql.Connector); isSQL {
+		c.Timeout = *defaultTimeout
+	}
+	versionConnector, isVersion := connector.(ServerVersionConnector)
+	if !isVersion {
+		return 0, 0, false
+	}
+	edition, majorVersion, err = versionConnector.GetServerVersion(ctx)
+	if err != nil {
+		logger := provider.ResourceLogger("server", "plan")
+		logger.Debug().Err(err).Msgf("unable to read the version of [%s], checking on apply", serverKey(serverProp, data))
+		return 0, 0, false
+	}
+	return edition, majorVersion, true
+}
+
+// checkServerName records the name of the server a resource is managed on, and fails if
+// the resource is later read through a connection that resolves to a different server.
+func checkServerName(ctx context.Context, meta interface{}, data *schema.ResourceData) error {
+	connector, err := meta.(model.Provider).GetConnector(serverProp, data)
+	if err != nil {
+		return err
+	}
+	name, err := connector.(ServerConnector).GetServerName(ctx)
+	if err != nil {
+		return err
+	}
+	return setServerName(data, name)
+}
+
+// setServerName is checkServerName for reads that already returned the server name along with the
+// resource, which saves a round trip per resource.
+func setServerName(data *schema.ResourceData, name string) error {
+	if stored := data.Get(serverNameProp).(string); stored != "" && !strings.EqualFold(stored, name) {
+		return fmt.Errorf("resource is managed on server [%s], but [%s] is now server [%s]", stored, serverKey(serverProp, data), name)
+	}
+	return data.Set(serverNameProp, name)
 }
@@ -0,0 +1,63 @@
+package mssql
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvOrFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MSSQL_TEST_SECRET", "")
+	t.Setenv("MSSQL_TEST_SECRET_FILE", path)
+	if value, err := envOrFile("MSSQL_TEST_SECRET"); err != nil || value != "s3cret" {
+		t.Errorf("expected secret from file, got %q, %v", value, err)
+	}
+
+	t.Setenv("MSSQL_TEST_SECRET", "from-env")
+	if value, err := envOrFile("MSSQL_TEST_SECRET"); err != nil || value != "from-env" {
+		t.Errorf("expected environment variable to take precedence, got %q, %v", value, err)
+	}
+
+	t.Setenv("MSSQL_TEST_SECRET", "")
+	t.Setenv("MSSQL_TEST_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+	if _, err := envOrFile("MSSQL_TEST_SECRET"); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+}
+
+func TestRequiredEnvDefaultFunc(t *testing.T) {
+	t.Setenv("MSSQL_USERNAME", "sa")
+	t.Setenv("MSSQL_PASSWORD", "")
+	t.Setenv("MSSQL_PASSWORD_FILE", "")
+	t.Setenv("MSSQL_TENANT_ID", "tenant")
+	t.Setenv("MSSQL_CLIENT_ID", "client")
+	t.Setenv("MSSQL_CLIENT_SECRET", "secret")
+
+	if value, err := requiredEnvDefaultFunc("login", "username", "MSSQL_USERNAME")(); err != nil || value != "sa" {
+		t.Errorf("expected username from environment, got %v, %v", value, err)
+	}
+
+	_, err := requiredEnvDefaultFunc("login", "password", "MSSQL_PASSWORD")()
+	if err == nil {
+		t.Fatal("expected error for missing password")
+	}
+	for _, s := range []string{"MSSQL_PASSWORD or MSSQL_PASSWORD_FILE", "MSSQL_PASSWORD is not set", "azure_login block may have been meant"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error containing [%s], got [%s]", s, err)
+		}
+	}
+
+	t.Setenv("MSSQL_CLIENT_SECRET", "")
+	t.Setenv("MSSQL_CLIENT_ID", "")
+	_, err = requiredEnvDefaultFunc("azure_login", "client_secret", "MSSQL_CLIENT_SECRET")()
+	if err == nil || !strings.Contains(err.Error(), "MSSQL_CLIENT_ID and MSSQL_CLIENT_SECRET are not set") {
+		t.Errorf("expected error naming the missing variables, got [%v]", err)
+	}
+}
@@ -1,27 +1,266 @@
 package mssql
 
 import (
+  "context"
+  "encoding/hex"
   "fmt"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
   "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+  "github.com/pkg/errors"
   "github.com/rs/zerolog"
+  "strings"
+  "time"
   "github.com/betr-io/terraform-provider-mssql/mssql/model"
+  "github.com/betr-io/terraform-provider-mssql/sql"
 )
 
 func getLoginID(data *schema.ResourceData) string {
   host := data.Get(serverProp + ".0.host").(string)
   port := data.Get(serverProp + ".0.port").(string)
-  loginName := data.Get(loginNameProp).(string)
+  loginName := trimName(data.Get(loginNameProp))
   return fmt.Sprintf("sqlserver://%s:%s/%s", host, port, loginName)
 }
 
+// getUserID identifies the user by its principal id rather than its name, so the ID is kept when the user is
+// renamed.
 func getUserID(data *schema.ResourceData) string {
   host := data.Get(serverProp + ".0.host").(string)
   port := data.Get(serverProp + ".0.port").(string)
   database := data.Get(databaseProp).(string)
-  username := data.Get(usernameProp).(string)
-  return fmt.Sprintf("sqlserver://%s:%s/%s/%s", host, port, database, username)
+  principalID := data.Get(principalIdProp).(int)
+  return fmt.Sprintf("sqlserver://%s:%s/%s/%d", host, port, database, principalID)
+}
+
+// serverFromRawState returns the host and port of the server block of a raw state, as passed to state
+// upgraders. ok is false if the state has no server block.
+func serverFromRawState(rawState map[string]interface{}) (host, port string, ok bool) {
+  servers, _ := rawState[serverProp].([]interface{})
+  if len(servers) == 0 {
+    return "", "", false
+  }
+  server, _ := servers[0].(map[string]interface{})
+  host, _ = server["host"].(string)
+  port, _ = server["port"].(string)
+  if host == "" {
+    return "", "", false
+  }
+  if port == "" {
+    port = DefaultPort
+  }
+  return host, port, true
+}
+
+// getTriggeredID returns a unique ID for resources that perform an operation rather than manage an object.
+func getTriggeredID(data *schema.ResourceData, kind string) string {
+  host := data.Get(serverProp + ".0.host").(string)
+  port := data.Get(serverProp + ".0.port").(string)
+  database := data.Get(databaseProp).(string)
+  return fmt.Sprintf("sqlserver://%s:%s/%s/%s/%s", host, port, database, kind, id.UniqueId())
 }
 
 func loggerFromMeta(meta interface{}, resource, function string) zerolog.Logger {
   return meta.(model.Provider).ResourceLogger(resource, function)
 }
+
+func checkDatabaseExists(ctx context.Context, meta interface{}, data *schema.ResourceData, database string) error {
+  exists, err := meta.(model.Provider).DatabaseExists(ctx, serverProp, data, database)
+  if err != nil {
+    return err
+  }
+  if !exists {
+    return errors.Errorf("database [%s] does not exist on server [%s]", database, serverKey(serverProp, data))
+  }
+  return nil
+}
+
+// checkDatabaseWritable fails if the database is READ_ONLY, e.g. by mssql_database_state in the same
+// configuration, instead of leaving it to the error of the first statement that writes to the database.
+func checkDatabaseWritable(ctx context.Context, meta interface{}, data *schema.ResourceData, database string) error {
+  connector, err := meta.(model.Provider).GetConnector(serverProp, data)
+  if err != nil {
+    return err
+  }
+  updateability, err := connector.(DatabaseUpdateabilityConnector).GetDatabaseUpdateability(ctx, database)
+  if err != nil {
+    return errors.Wrapf(err, "unable to check whether database [%s] is READ_ONLY", database)
+  }
+  if updateability == "READ_ONLY" {
+    return errors.Errorf("database [%s] is READ_ONLY; set it READ_WRITE before managing principals or other contents", database)
+  }
+  return nil
+}
+
+type MissingObjectConnector interface {
+  DatabaseMissing(ctx context.Context, database string) (bool, error)
+  PrincipalMissing(ctx context.Context, database, name string) (bool, error)
+}
+
+// readFailed returns the diagnostics of a read that failed with err. With ignore_missing_objects, a read that
+// failed because the object or its database does not exist instead removes the resource from state with a
+// warning, so that it does not block the refresh of the rest of the state.
+func readFailed(ctx context.Context, meta interface{}, data *schema.ResourceData, err error) diag.Diagnostics {
+  provider := meta.(model.Provider)
+  if !provider.IgnoreMissingObjects() || !objectMissing(ctx, meta, data, err) {
+    return diag.FromErr(err)
+  }
+  data.SetId("")
+  return diag.Diagnostics{{
+    Severity: diag.Warning,
+    Summary:  "Resource removed from state, as its object is missing",
+    Detail:   err.Error(),
+  }}
+}
+
+// objectMissing reports whether a read failed with err because its object or database does not exist. The
+// errors for a missing database or principal are also raised for lack of permission, so for those the database
+// of the resource, or its login or user, is looked up to confirm that it is missing.
+func objectMissing(ctx context.Context, meta interface{}, data *schema.ResourceData, err error) bool {
+  if sql.IsMissingObjectError(err) {
+    return true
+  }
+  if !sql.IsMissingDatabaseError(err) && !sql.IsMissingPrincipalError(err) {
+    return false
+  }
+  connector, cerr := meta.(model.Provider).GetConnector(serverProp, data)
+  if cerr != nil {
+    return false
+  }
+  checker, ok := connector.(MissingObjectConnector)
+  if !ok {
+    return false
+  }
+  // Not every resource has a database, login or user, in which case the error cannot be confirmed
+  database, _ := data.Get(databaseProp).(string)
+  var missing bool
+  switch {
+  case sql.IsMissingDatabaseError(err) && database != "":
+    missing, cerr = checker.DatabaseMissing(ctx, database)
+  case sql.IsMissingPrincipalError(err) && database != "" && data.Get(usernameProp) != nil:
+    missing, cerr = checker.PrincipalMissing(ctx, database, data.Get(usernameProp).(string))
+  case sql.IsMissingPrincipalError(err) && database == "" && data.Get(loginNameProp) != nil:
+    missing, cerr = checker.PrincipalMissing(ctx, "", trimName(data.Get(loginNameProp)))
+  }
+  return cerr == nil && missing
+}
+
+// checkModifyDate stores the modify_date of a principal, and with track_modifications warns when it has
+// changed since it was last stored, i.e. the principal was altered outside Terraform. Create and update
+// clear the stored value first, so changes made by the provider itself are not reported.
+func checkModifyDate(data *schema.ResourceData, principal, modifyDate string) diag.Diagnostics {
+  var diags diag.Diagnostics
+  stored := data.Get(modifyDateProp).(string)
+  if data.Get(trackModificationsProp).(bool) && stored != "" && stored != modifyDate {
+    diags = append(diags, diag.Diagnostic{
+      Severity: diag.Warning,
+      Summary:  fmt.Sprintf("%s was modified outside Terraform", principal),
+      Detail:   fmt.Sprintf("%s was last modified at %s, but was at %s when last seen by Terraform.", principal, modifyDate, stored),
+    })
+  }
+  if err := data.Set(modifyDateProp, modifyDate); err != nil {
+    diags = append(diags, diag.FromErr(err)...)
+  }
+  return diags
+}
+
+// verifyAccess retries verify until it reports no missing access or the timeout expires, as grants may take
+// a moment to take effect. It then fails with the access still missing, e.g. because of a DENY elsewhere.
+func verifyAccess(ctx context.Context, principal string, timeout time.Duration, verify func() ([]string, error)) error {
+  var problems []string
+  err := retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+    var err error
+    if problems, err = verify(); err != nil {
+      return retry.NonRetryableError(errors.Wrapf(err, "unable to verify access of %s", principal))
+    }
+    if len(problems) > 0 {
+      return retry.RetryableError(errors.Errorf("%s has %s", principal, strings.Join(problems, ", ")))
+    }
+    return nil
+  })
+  if err != nil && len(problems) > 0 {
+    return errors.Errorf("access of %s could not be verified within %s: %s", principal, timeout, strings.Join(problems, ", "))
+  }
+  return err
+}
+
+// trimName is the StateFunc of principal names, which are easily copied with surrounding whitespace that SQL
+// Server would either keep or ignore in comparisons.
+func trimName(v interface{}) string {
+  return strings.TrimSpace(v.(string))
+}
+
+// suppressNameWhitespaceDiff suppresses differences in surrounding whitespace, so that state written before
+// names were trimmed does not force the resource to be replaced.
+func suppressNameWhitespaceDiff(k, old, new string, d *schema.ResourceData) bool {
+  return strings.TrimSpace(old) == strings.TrimSpace(new)
+}
+
+// closeNames returns the names that differ from name only in case and surrounding whitespace, or by at most two
+// characters, as suggestions for a name that does not exist.
+func closeNames(name string, names []string) []string {
+  name = strings.ToLower(strings.TrimSpace(name))
+  var matches []string
+  for _, candidate := range names {
+    if editDistance(name, strings.ToLower(candidate)) <= 2 {
+      matches = append(matches, candidate)
+    }
+  }
+  return matches
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+  s, t := []rune(a), []rune(b)
+  previous := make([]int, len(t)+1)
+  for j := range previous {
+    previous[j] = j
+  }
+  for i := 1; i <= len(s); i++ {
+    current := make([]int, len(t)+1)
+    current[0] = i
+    for j := 1; j <= len(t); j++ {
+      cost := 1
+      if s[i-1] == t[j-1] {
+        cost = 0
+      }
+      current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+    }
+    previous = current
+  }
+  return previous[len(t)]
+}
+
+// objectIdSID returns the SID SQL Server gives a principal from Azure AD with the object id, as
+// CONVERT(VARCHAR(1000), sid, 1) shows it. The SID holds the bytes of the object id as a uniqueidentifier,
+// which stores its first three groups little-endian.
+func objectIdSID(objectId string) (string, error) {
+  b, err := hex.DecodeString(strings.ReplaceAll(objectId, "-", ""))
+  if err != nil || len(b) != 16 {
+    return "", errors.Errorf("%s [%s] is not a GUID", objectIdProp, objectId)
+  }
+  b[0], b[1], b[2], b[3] = b[3], b[2], b[1], b[0]
+  b[4], b[5] = b[5], b[4]
+  b[6], b[7] = b[7], b[6]
+  return "0x" + strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// checkExistingSID checks that an existing principal from Windows or Azure AD is the one the configuration
+// names. Only the SID of an EXTERNAL principal with an object_id is known in advance; other SIDs are resolved
+// from the name by the directory when the principal is created, so an existing principal can be a different,
+// e.g. deleted and recreated, account, and is not adopted.
+func checkExistingSID(principal, existingSID, objectId string) error {
+  if objectId == "" {
+    return errors.Errorf("%s already exists, and without %s it cannot be verified to be the same principal; import it with terraform import, or drop it, before applying",
+      principal, objectIdProp)
+  }
+  sid, err := objectIdSID(objectId)
+  if err != nil {
+    return err
+  }
+  if !strings.EqualFold(existingSID, sid) {
+    return errors.Errorf("%s already exists with SID [%s] instead of [%s] of %s [%s]; import it with terraform import, or drop it, before applying",
+      principal, existingSID, sid, objectIdProp, objectId)
+  }
+  return nil
+}
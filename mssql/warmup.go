@@ -0,0 +1,169 @@
+package mssql
+
+import (
+  "context"
+  "fmt"
+  "github.com/betr-io/terraform-provider-mssql/sql"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+  "github.com/pkg/errors"
+  "sort"
+  "strings"
+  "sync"
+)
+
+// connectionWarmup remembers the connection targets already validated with connection_warmup, so each distinct
+// server, database and login is only connected to once per run, and collects the results so they can be reported
+// together.
+type connectionWarmup struct {
+  mu       sync.Mutex
+  done     *sync.Cond
+  targets  map[string]bool
+  inFlight int
+  results  []warmupResult
+  reported bool
+}
+
+type warmupResult struct {
+  target string
+  err    error
+}
+
+func newConnectionWarmup() *connectionWarmup {
+  w := &connectionWarmup{targets: make(map[string]bool)}
+  w.done = sync.NewCond(&w.mu)
+  return w
+}
+
+// firstUse reports whether key has not been seen before, and marks it as seen. A check of key is then in flight
+// until finish is called.
+func (w *connectionWarmup) firstUse(key string) bool {
+  w.mu.Lock()
+  defer w.mu.Unlock()
+  if w.targets[key] {
+    return false
+  }
+  w.targets[key] = true
+  w.inFlight++
+  return true
+}
+
+// finish records the result of checking target. The first check to fail waits for the checks in flight, and
+// returns a single error listing the reachable and unreachable targets. Later failures return nil, as the plan
+// already fails.
+func (w *connectionWarmup) finish(target string, err error) error {
+  w.mu.Lock()
+  defer w.mu.Unlock()
+  w.results = append(w.results, warmupResult{target: target, err: err})
+  w.inFlight--
+  w.done.Broadcast()
+  if err == nil {
+    return nil
+  }
+  for w.inFlight > 0 {
+    w.done.Wait()
+  }
+  if w.reported {
+    return nil
+  }
+  w.reported = true
+  return errors.New(w.report())
+}
+
+func (w *connectionWarmup) report() string {
+  results := make([]warmupResult, len(w.results))
+  copy(results, w.results)
+  sort.Slice(results, func(i, j int) bool { return results[i].target < results[j].target })
+  var unreachable, reachable []string
+  for _, r := range results {
+    if r.err != nil {
+      unreachable = append(unreachable, fmt.Sprintf("  %s: %v", r.target, r.err))
+    } else {
+      reachable = append(reachable, "  "+r.target)
+    }
+  }
+  msg := fmt.Sprintf("connection_warmup: %d of %d targets are unreachable:\n%s", len(unreachable), len(results), strings.Join(unreachable, "\n"))
+  if len(reachable) > 0 {
+    msg += "\nreachable:\n" + strings.Join(reachable, "\n")
+  }
+  return msg
+}
+
+// withConnectionWarmup makes the plan of the resource connect to its server and database when connection_warmup
+// is set, so that connection and login problems with every target are reported by the plan rather than one at a
+// time during apply.
+func withConnectionWarmup(resource *schema.Resource) {
+  if _, ok := resource.Schema[serverProp]; !ok {
+    return
+  }
+  customizeDiff := resource.CustomizeDiff
+  resource.CustomizeDiff = func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+    if p, ok := meta.(mssqlProvider); ok && p.warmup != nil {
+      if err := p.warmup.check(ctx, p, resource, diff); err != nil {
+        return err
+      }
+    }
+    if customizeDiff == nil {
+      return nil
+    }
+    return customizeDiff(ctx, diff, meta)
+  }
+}
+
+// check connects to the target of diff, unless it was already checked or is not known until apply.
+func (w *connectionWarmup) check(ctx context.Context, p mssqlProvider, resource *schema.Resource, diff *schema.ResourceDiff) error {
+  config := diff.GetRawConfig()
+  if config.IsNull() || !config.GetAttr(serverProp).IsWhollyKnown() {
+    return nil
+  }
+  database := "master"
+  if _, ok := resource.Schema[databaseProp]; ok {
+    if !config.GetAttr(databaseProp).IsKnown() {
+      return nil
+    }
+    if v := diff.Get(databaseProp).(string); v != "" {
+      database = v
+    }
+  }
+
+  data := resource.Data(nil)
+  if err := data.Set(serverProp, diff.Get(serverProp)); err != nil {
+    return err
+  }
+  target := fmt.Sprintf("%s/%s", serverKey(serverProp, data), database)
+  // The login is part of the key, as the same database can be reached with different logins
+  if !w.firstUse(fmt.Sprintf("%s %v", target, diff.Get(serverProp))) {
+    return nil
+  }
+  return w.finish(target, w.ping(ctx, p, data, database, target))
+}
+
+func (w *connectionWarmup) ping(ctx context.Context, p mssqlProvider, data *schema.ResourceData, database, target string) error {
+  logger := p.ResourceLogger("connection_warmup", "check")
+  if database != "master" {
+    exists, err := p.DatabaseExists(ctx, serverProp, data, database)
+    if err != nil {
+      return err
+    }
+    if !exists {
+      // The database may be created by the apply, so only the login to the server is checked
+      logger.Info().Msgf("[%s] does not exist yet, checking the server only", target)
+      database = "master"
+    }
+  }
+  connector, err := p.GetConnector(serverProp, data)
+  if err != nil {
+    return err
+  }
+  c, ok := connector.(*sql.Connector)
+  if !ok {
+    return errors.Errorf("unexpected connector type %T", connector)
+  }
+  c.Database = database
+  c.Timeout = *defaultTimeout
+  if err = c.PingContext(ctx); err != nil {
+    logger.Info().Err(err).Msgf("[%s] is unreachable", target)
+    return err
+  }
+  logger.Info().Msgf("[%s] is reachable", target)
+  return nil
+}
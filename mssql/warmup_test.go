@@ -0,0 +1,75 @@
+package mssql
+
+import (
+  "context"
+  "errors"
+  "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+  "testing"
+)
+
+func TestConnectionWarmupFirstUse(t *testing.T) {
+  warmup := newConnectionWarmup()
+  if !warmup.firstUse("localhost:1433/master") {
+    t.Error("expected first use of target")
+  }
+  if warmup.firstUse("localhost:1433/master") {
+    t.Error("expected target to be checked only once")
+  }
+  if !warmup.firstUse("localhost:1433/app") {
+    t.Error("expected first use of another database")
+  }
+}
+
+func TestConnectionWarmupFinish(t *testing.T) {
+  warmup := newConnectionWarmup()
+  warmup.firstUse("a")
+  warmup.firstUse("b")
+  warmup.firstUse("c")
+  if err := warmup.finish("localhost:1433/master", nil); err != nil {
+    t.Fatalf("unexpected error: %v", err)
+  }
+  go func() {
+    _ = warmup.finish("localhost:1433/app", nil)
+  }()
+  // The first failure waits for the check of localhost:1433/app, and reports every target
+  err := warmup.finish("remote:1433/master", errors.New("login failed"))
+  if err == nil {
+    t.Fatal("expected an error for the unreachable target")
+  }
+  expected := "connection_warmup: 1 of 3 targets are unreachable:\n  remote:1433/master: login failed\nreachable:\n  localhost:1433/app\n  localhost:1433/master"
+  if err.Error() != expected {
+    t.Errorf("expected [%s], got [%s]", expected, err.Error())
+  }
+
+  warmup.firstUse("d")
+  if err = warmup.finish("other:1433/master", errors.New("timeout")); err != nil {
+    t.Errorf("expected later failures not to be reported again, got %v", err)
+  }
+}
+
+func TestWithConnectionWarmup(t *testing.T) {
+  called := false
+  resource := &schema.Resource{
+    Schema: map[string]*schema.Schema{
+      serverProp: {Type: schema.TypeList, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{}}},
+    },
+    CustomizeDiff: func(context.Context, *schema.ResourceDiff, interface{}) error {
+      called = true
+      return nil
+    },
+  }
+  withConnectionWarmup(resource)
+  // Without connection_warmup only the original CustomizeDiff runs
+  if err := resource.CustomizeDiff(context.Background(), nil, mssqlProvider{}); err != nil {
+    t.Fatalf("unexpected error: %v", err)
+  }
+  if !called {
+    t.Error("expected the original CustomizeDiff to be called")
+  }
+
+  other := &schema.Resource{Schema: map[string]*schema.Schema{}}
+  withConnectionWarmup(other)
+  if other.CustomizeDiff != nil {
+    t.Error("expected resources without a server block to be left as they are")
+  }
+}
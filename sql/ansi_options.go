@@ -0,0 +1,93 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "fmt"
+  "sort"
+  "strings"
+)
+
+// databaseAnsiOptions are the options of ALTER DATABASE ... SET managed by GetDatabaseAnsiOptions and
+// SetDatabaseAnsiOptions, with their column in sys.databases.
+var databaseAnsiOptions = map[string]string{
+  "ANSI_NULLS":              "is_ansi_nulls_on",
+  "ANSI_PADDING":            "is_ansi_padding_on",
+  "ANSI_WARNINGS":           "is_ansi_warnings_on",
+  "ARITHABORT":              "is_arithabort_on",
+  "CONCAT_NULL_YIELDS_NULL": "is_concat_null_yields_null_on",
+  "QUOTED_IDENTIFIER":       "is_quoted_identifier_on",
+  "NUMERIC_ROUNDABORT":      "is_numeric_roundabort_on",
+}
+
+// GetDatabaseAnsiOptions returns whether each of the databaseAnsiOptions is on, or nil if the database does not exist.
+func (c *Connector) GetDatabaseAnsiOptions(ctx context.Context, database string) (map[string]bool, error) {
+  names := make([]string, 0, len(databaseAnsiOptions))
+  columns := make([]string, 0, len(databaseAnsiOptions))
+  for name := range databaseAnsiOptions {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  for _, name := range names {
+    columns = append(columns, databaseAnsiOptions[name])
+  }
+  cmd := `SELECT ` + strings.Join(columns, ", ") + ` FROM [sys].[databases] WHERE name = @database`
+  values := make([]bool, len(names))
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        dest := make([]interface{}, len(values))
+        for i := range values {
+          dest[i] = &values[i]
+        }
+        return r.Scan(dest...)
+      },
+      sql.Named("database", database),
+    )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+  options := make(map[string]bool, len(names))
+  for i, name := range names {
+    options[name] = values[i]
+  }
+  return options, nil
+}
+
+// SetDatabaseAnsiOptions sets the given options in a single ALTER DATABASE statement. Only options in
+// databaseAnsiOptions are accepted.
+func (c *Connector) SetDatabaseAnsiOptions(ctx context.Context, database string, options map[string]bool) error {
+  names := make([]string, 0, len(options))
+  for name := range options {
+    if _, ok := databaseAnsiOptions[name]; !ok {
+      return fmt.Errorf("unknown database option [%s]", name)
+    }
+    names = append(names, name)
+  }
+  if len(names) == 0 {
+    return nil
+  }
+  sort.Strings(names)
+
+  settings := make([]string, len(names))
+  for i, name := range names {
+    if options[name] {
+      settings[i] = name + " ON"
+    } else {
+      settings[i] = name + " OFF"
+    }
+  }
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'ALTER DATABASE ' + QuoteName(@database) + ' SET ' + @settings
+          EXEC (@sql)`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("database", database),
+      sql.Named("settings", strings.Join(settings, ", ")),
+    )
+}
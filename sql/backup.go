@@ -0,0 +1,111 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+func (c *Connector) BackupDatabase(ctx context.Context, backup *model.Backup) error {
+  // QuoteName is limited to 128 characters, which is too short for URLs and paths
+  cmd := `DECLARE @sql nvarchar(max)
+          DECLARE @options nvarchar(max) = ''
+          SET @sql = 'BACKUP DATABASE ' + QuoteName(@database) + ' TO ' +
+                     CASE WHEN @url != '' THEN 'URL = ''' + REPLACE(@url, '''', '''''') + ''''
+                          ELSE 'DISK = ''' + REPLACE(@disk, '''', '''''') + '''' END
+          IF @copyOnly = 1 SET @options = @options + ', COPY_ONLY'
+          IF @credential != '' SET @options = @options + ', CREDENTIAL = ' + QuoteName(@credential, '''')
+          IF @options != '' SET @sql = @sql + ' WITH ' + STUFF(@options, 1, 2, '')
+          EXEC (@sql)`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("database", backup.Database),
+      sql.Named("url", backup.Url),
+      sql.Named("disk", backup.Disk),
+      sql.Named("credential", backup.Credential),
+      sql.Named("copyOnly", backup.CopyOnly),
+    )
+}
+
+func (c *Connector) GetBackup(ctx context.Context, backup *model.Backup) error {
+  cmd := `SELECT TOP 1 CONVERT(VARCHAR(33), bs.backup_finish_date, 126), CAST(bs.last_lsn AS VARCHAR(30))
+          FROM [msdb].[dbo].[backupset] bs
+            INNER JOIN [msdb].[dbo].[backupmediafamily] mf ON bs.media_set_id = mf.media_set_id
+          WHERE bs.database_name = @database AND mf.physical_device_name = CASE WHEN @url != '' THEN @url ELSE @disk END
+          ORDER BY bs.backup_finish_date DESC`
+  err := c.QueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&backup.FinishDate, &backup.LastLSN)
+    },
+    sql.Named("database", backup.Database),
+    sql.Named("url", backup.Url),
+    sql.Named("disk", backup.Disk),
+  )
+  if err == sql.ErrNoRows {
+    return nil
+  }
+  return err
+}
+
+func (c *Connector) RestoreDatabase(ctx context.Context, restore *model.Restore) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          DECLARE @options nvarchar(max) = ''
+          SET @sql = 'RESTORE DATABASE ' + QuoteName(@database) + ' FROM ' +
+                     CASE WHEN @url != '' THEN 'URL = ''' + REPLACE(@url, '''', '''''') + ''''
+                          ELSE 'DISK = ''' + REPLACE(@disk, '''', '''''') + '''' END
+          IF @replace = 1 SET @options = @options + ', REPLACE'
+          IF @credential != '' SET @options = @options + ', CREDENTIAL = ' + QuoteName(@credential, '''')
+          IF @options != '' SET @sql = @sql + ' WITH ' + STUFF(@options, 1, 2, '')
+          EXEC (@sql)`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("database", restore.Database),
+      sql.Named("url", restore.Url),
+      sql.Named("disk", restore.Disk),
+      sql.Named("credential", restore.Credential),
+      sql.Named("replace", restore.Replace),
+    )
+}
+
+func (c *Connector) GetRestore(ctx context.Context, restore *model.Restore) error {
+  cmd := `SELECT TOP 1 CONVERT(VARCHAR(33), restore_date, 126)
+          FROM [msdb].[dbo].[restorehistory]
+          WHERE destination_database_name = @database
+          ORDER BY restore_date DESC`
+  err := c.QueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&restore.RestoreDate)
+    },
+    sql.Named("database", restore.Database),
+  )
+  if err == sql.ErrNoRows {
+    return nil
+  }
+  return err
+}
+
+// GetOperationProgress returns the percentage completed of a running command (e.g. BACKUP DATABASE) against a database.
+func (c *Connector) GetOperationProgress(ctx context.Context, command, database string) (float64, bool, error) {
+  cmd := `SELECT TOP 1 r.percent_complete FROM [sys].[dm_exec_requests] r
+            CROSS APPLY [sys].[dm_exec_sql_text](r.sql_handle) t
+          WHERE r.command = @command AND t.text LIKE @command + ' ' + REPLACE(QuoteName(@database), '[', '[[]') + '%'`
+  var progress float64
+  err := c.QueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&progress)
+    },
+    sql.Named("command", command),
+    sql.Named("database", database),
+  )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return 0, false, nil
+    }
+    return 0, false, err
+  }
+  return progress, true, nil
+}
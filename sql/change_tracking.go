@@ -0,0 +1,97 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+  "github.com/pkg/errors"
+)
+
+func (c *Connector) GetChangeTracking(ctx context.Context, database string) (*model.ChangeTracking, error) {
+  cmd := `SELECT retention_period, retention_period_units_desc, is_auto_cleanup_on
+          FROM [sys].[change_tracking_databases] WHERE database_id = DB_ID(@database)`
+  tracking := model.ChangeTracking{Database: database}
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&tracking.RetentionPeriod, &tracking.RetentionPeriodUnits, &tracking.AutoCleanup)
+      },
+      sql.Named("database", database),
+    )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+  return &tracking, nil
+}
+
+// SetChangeTracking enables change tracking on the database, or changes its options when already enabled.
+func (c *Connector) SetChangeTracking(ctx context.Context, tracking *model.ChangeTracking) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'ALTER DATABASE ' + QuoteName(@database) + ' SET CHANGE_TRACKING ' +
+                     CASE WHEN EXISTS (SELECT 1 FROM [sys].[change_tracking_databases] WHERE database_id = DB_ID(@database)) THEN '' ELSE '= ON ' END +
+                     '(CHANGE_RETENTION = ' + CAST(@retentionPeriod AS nvarchar(10)) + ' ' + @retentionPeriodUnits + ', ' +
+                     'AUTO_CLEANUP = ' + CASE WHEN @autoCleanup = 1 THEN 'ON' ELSE 'OFF' END + ')'
+          EXEC (@sql)`
+  database := tracking.Database
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("database", tracking.Database),
+      sql.Named("retentionPeriod", tracking.RetentionPeriod),
+      sql.Named("retentionPeriodUnits", tracking.RetentionPeriodUnits),
+      sql.Named("autoCleanup", tracking.AutoCleanup),
+    )
+}
+
+func (c *Connector) DisableChangeTracking(ctx context.Context, database string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'IF EXISTS (SELECT 1 FROM [sys].[change_tracking_databases] WHERE database_id = DB_ID(' + QuoteName(@database, '''') + ')) ' +
+                     'ALTER DATABASE ' + QuoteName(@database) + ' SET CHANGE_TRACKING = OFF'
+          EXEC (@sql)`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("database", database))
+}
+
+func (c *Connector) GetCDCEnabled(ctx context.Context, database string) (bool, error) {
+  cmd := `SELECT is_cdc_enabled FROM [sys].[databases] WHERE name = @database`
+  var enabled bool
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&enabled)
+      },
+      sql.Named("database", database),
+    )
+  if err == sql.ErrNoRows {
+    return false, nil
+  }
+  return enabled, err
+}
+
+func (c *Connector) EnableCDC(ctx context.Context, database string) error {
+  edition, err := c.setDatabase(&database).GetEngineEdition(ctx)
+  if err != nil {
+    return err
+  }
+  if edition == 4 {
+    return errors.New("change data capture is not supported by SQL Server Express")
+  }
+  if edition == 6 || edition == 11 {
+    return errors.New("change data capture is not supported by Azure Synapse Analytics")
+  }
+  return c.ExecContext(ctx, "EXEC sys.sp_cdc_enable_db")
+}
+
+func (c *Connector) DisableCDC(ctx context.Context, database string) error {
+  cmd := `IF EXISTS (SELECT 1 FROM [sys].[databases] WHERE name = DB_NAME() AND is_cdc_enabled = 1)
+            EXEC sys.sp_cdc_disable_db`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd)
+}
@@ -0,0 +1,85 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "fmt"
+  "sort"
+  "strings"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+func (c *Connector) GetConfigurations(ctx context.Context) ([]model.Configuration, error) {
+  cmd := `SELECT name, CAST(value AS bigint), CAST(value_in_use AS bigint) FROM [sys].[configurations]`
+  var configurations []model.Configuration
+  database := "master"
+  err := c.
+    setDatabase(&database).
+    QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        var configuration model.Configuration
+        if err := r.Scan(&configuration.Name, &configuration.Value, &configuration.ValueInUse); err != nil {
+          return err
+        }
+        configurations = append(configurations, configuration)
+      }
+      return r.Err()
+    })
+  if err != nil {
+    return nil, err
+  }
+  return configurations, nil
+}
+
+// SetConfigurations applies all settings with sp_configure followed by a single RECONFIGURE. If any
+// setting fails, or RECONFIGURE rejects one of the values, the settings already applied are reverted
+// before the error is raised, so that no value is left pending in sys.configurations. Advanced options
+// are made visible while the settings are applied, unless `show advanced options` is itself managed.
+func (c *Connector) SetConfigurations(ctx context.Context, settings map[string]int64) error {
+  names := make([]string, 0, len(settings))
+  for name := range settings {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+
+  var apply, revert strings.Builder
+  args := []interface{}{sql.Named("restoreAdvanced", true)}
+  for i, name := range names {
+    fmt.Fprintf(&apply, "EXEC sp_configure @name%d, @value%d\n", i, i)
+    fmt.Fprintf(&revert, "SET @old = (SELECT CAST(value AS bigint) FROM @previous WHERE name = @name%d)\n", i)
+    fmt.Fprintf(&revert, "IF @old IS NOT NULL EXEC sp_configure @name%d, @old\n", i)
+    args = append(args, sql.Named(fmt.Sprintf("name%d", i), name), sql.Named(fmt.Sprintf("value%d", i), settings[name]))
+    if strings.EqualFold(name, "show advanced options") {
+      args[0] = sql.Named("restoreAdvanced", false)
+    }
+  }
+
+  cmd := `DECLARE @previous TABLE (name nvarchar(35), value sql_variant)
+          INSERT INTO @previous SELECT name, value FROM [sys].[configurations]
+          DECLARE @advanced bigint = (SELECT CAST(value AS bigint) FROM [sys].[configurations] WHERE name = 'show advanced options')
+          DECLARE @old bigint
+          IF @advanced = 0
+            BEGIN
+              EXEC sp_configure 'show advanced options', 1
+              RECONFIGURE
+            END
+          BEGIN TRY
+          ` + apply.String() + `
+            RECONFIGURE
+          END TRY
+          BEGIN CATCH
+          ` + revert.String() + `
+            IF @advanced = 0 AND @restoreAdvanced = 1 EXEC sp_configure 'show advanced options', 0
+            RECONFIGURE;
+            THROW
+          END CATCH
+          IF @advanced = 0 AND @restoreAdvanced = 1
+            BEGIN
+              EXEC sp_configure 'show advanced options', 0
+              RECONFIGURE
+            END`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, args...)
+}
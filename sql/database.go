@@ -0,0 +1,54 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+)
+
+func (c *Connector) GetDatabases(ctx context.Context) ([]string, error) {
+  // Azure SQL Database only lists master and the current database unless connected to master
+  cmd := `SELECT name FROM [sys].[databases]
+          WHERE NOT (SERVERPROPERTY('EngineEdition') = 5 AND DB_NAME() != 'master')`
+  var databases []string
+  err := c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+    for r.Next() {
+      var name string
+      if err := r.Scan(&name); err != nil {
+        return err
+      }
+      databases = append(databases, name)
+    }
+    return r.Err()
+  })
+  if err != nil {
+    return nil, err
+  }
+  return databases, nil
+}
+
+// GetDatabaseUpdateability returns whether the database is READ_ONLY or READ_WRITE.
+func (c *Connector) GetDatabaseUpdateability(ctx context.Context, database string) (string, error) {
+  cmd := `SELECT COALESCE(CAST(DATABASEPROPERTYEX(DB_NAME(), 'Updateability') AS nvarchar(128)), '')`
+  var updateability string
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd, func(r *sql.Row) error {
+      return r.Scan(&updateability)
+    })
+  return updateability, err
+}
+
+// DatabaseMissing reports whether the database does not exist. It is only reported missing if the connected
+// principal has VIEW ANY DATABASE permission, as sys.databases hides the databases it cannot access otherwise.
+func (c *Connector) DatabaseMissing(ctx context.Context, database string) (bool, error) {
+  cmd := `SELECT CAST(CASE WHEN DB_ID(@database) IS NULL AND HAS_PERMS_BY_NAME(NULL, NULL, 'VIEW ANY DATABASE') = 1 THEN 1 ELSE 0 END AS bit)`
+  var missing bool
+  master := "master"
+  err := c.
+    setDatabase(&master).
+    QueryRowContext(ctx, cmd, func(r *sql.Row) error {
+      return r.Scan(&missing)
+    },
+    sql.Named("database", database))
+  return missing, err
+}
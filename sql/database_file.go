@@ -0,0 +1,39 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+// GetDatabaseFiles lists the files of database, with sizes converted from 8 KB pages to MB. FILEPROPERTY
+// only reports the used space of the files of the current database, so the query runs in database. Files it
+// does not report on, e.g. FILESTREAM containers, have no used space.
+func (c *Connector) GetDatabaseFiles(ctx context.Context, database string) ([]model.DatabaseFile, error) {
+  cmd := `SELECT file_id, name, type_desc, physical_name,
+                 CAST(size AS float) * 8 / 1024,
+                 CASE WHEN max_size = -1 THEN -1 ELSE CAST(max_size AS float) * 8 / 1024 END,
+                 CASE WHEN is_percent_growth = 1 THEN CAST(growth AS float) ELSE CAST(growth AS float) * 8 / 1024 END,
+                 is_percent_growth,
+                 COALESCE(CAST(FILEPROPERTY(name, 'SpaceUsed') AS float) * 8 / 1024, 0)
+          FROM [sys].[database_files]
+          ORDER BY file_id`
+  files := make([]model.DatabaseFile, 0)
+  err := c.
+    setDatabase(&database).
+    QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        var file model.DatabaseFile
+        if err := r.Scan(&file.FileID, &file.Name, &file.TypeDesc, &file.PhysicalName, &file.SizeMB, &file.MaxSizeMB,
+          &file.Growth, &file.IsPercentGrowth, &file.UsedSpaceMB); err != nil {
+          return err
+        }
+        files = append(files, file)
+      }
+      return r.Err()
+    })
+  if err != nil {
+    return nil, err
+  }
+  return files, nil
+}
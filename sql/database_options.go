@@ -0,0 +1,220 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "fmt"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+  "sort"
+  "strings"
+)
+
+// databaseSetOptions are the options managed by SetDatabaseOptions, with their accepted values.
+var databaseSetOptions = map[string][]string{
+  "AUTO_CLOSE":     {"ON", "OFF"},
+  "PAGE_VERIFY":    {"CHECKSUM", "TORN_PAGE_DETECTION", "NONE"},
+  "DATA_RETENTION": {"ON", "OFF"},
+}
+
+// databaseOptionEditions are the engine editions supporting the options that are not supported everywhere,
+// with a description for errors.
+var databaseOptionEditions = map[string]struct {
+  editions    []int
+  description string
+}{
+  "DATA_RETENTION": {[]int{9}, "Azure SQL Edge"},
+  "FILESTREAM":     {[]int{1, 2, 3, 4}, "SQL Server"},
+}
+
+// filestreamAccessValues are the accepted values of NON_TRANSACTED_ACCESS of SetDatabaseFilestream.
+var filestreamAccessValues = []string{"OFF", "READ_ONLY", "FULL"}
+
+// GetDatabaseOptions returns the options of the database, or nil if the database does not exist. Options that
+// the engine edition does not support are reported with the behaviour of the edition, i.e. data retention off
+// and FILESTREAM non-transacted access off without a directory name.
+func (c *Connector) GetDatabaseOptions(ctx context.Context, database string) (*model.DatabaseOptions, error) {
+  cmd := `SELECT is_auto_close_on, page_verify_option_desc FROM [sys].[databases] WHERE name = @database`
+  var options model.DatabaseOptions
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&options.AutoClose, &options.PageVerify)
+      },
+      sql.Named("database", database),
+    )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+
+  edition, err := c.GetEngineEdition(ctx)
+  if err != nil {
+    return nil, err
+  }
+  if supportsDatabaseOption(edition, "DATA_RETENTION") {
+    // The column only exists on Azure SQL Edge, so it cannot be part of the query above
+    cmd = `SELECT is_data_retention_enabled FROM [sys].[databases] WHERE name = @database`
+    err = c.QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&options.DataRetention)
+      },
+      sql.Named("database", database),
+    )
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  options.FilestreamNonTransactedAccess = "OFF"
+  if supportsDatabaseOption(edition, "FILESTREAM") {
+    cmd = `SELECT non_transacted_access_desc, COALESCE(directory_name, '')
+           FROM [sys].[database_filestream_options] WHERE database_id = DB_ID(@database)`
+    err = c.QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&options.FilestreamNonTransactedAccess, &options.FilestreamDirectoryName)
+      },
+      sql.Named("database", database),
+    )
+    if err != nil && err != sql.ErrNoRows {
+      return nil, err
+    }
+  }
+
+  cmd = `SELECT name FROM [sys].[filegroups] WHERE is_default = 1`
+  err = c.QueryRowContext(ctx, cmd, func(r *sql.Row) error {
+    return r.Scan(&options.DefaultFilegroup)
+  })
+  if err != nil {
+    return nil, err
+  }
+  return &options, nil
+}
+
+// SetDatabaseOptions sets the given options to their values with a single ALTER DATABASE statement. Only the
+// options and values in databaseSetOptions are accepted, and options that the engine edition does not support
+// fail before anything is changed.
+func (c *Connector) SetDatabaseOptions(ctx context.Context, database string, options map[string]string) error {
+  names := make([]string, 0, len(options))
+  for name, value := range options {
+    values, ok := databaseSetOptions[name]
+    if !ok {
+      return fmt.Errorf("unknown database option [%s]", name)
+    }
+    if !containsString(values, value) {
+      return fmt.Errorf("invalid value [%s] of database option [%s]", value, name)
+    }
+    names = append(names, name)
+  }
+  if len(names) == 0 {
+    return nil
+  }
+  sort.Strings(names)
+
+  c.setDatabase(&database)
+  edition := -1
+  for _, name := range names {
+    if _, ok := databaseOptionEditions[name]; !ok {
+      continue
+    }
+    if edition < 0 {
+      var err error
+      if edition, err = c.GetEngineEdition(ctx); err != nil {
+        return err
+      }
+    }
+    if !supportsDatabaseOption(edition, name) {
+      return fmt.Errorf("%s is not supported on this edition of SQL Server, only on %s", name, databaseOptionEditions[name].description)
+    }
+  }
+
+  settings := make([]string, len(names))
+  for i, name := range names {
+    settings[i] = name + " " + options[name]
+  }
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'ALTER DATABASE ' + QuoteName(@database) + ' SET ' + @settings
+          EXEC (@sql)`
+  return c.ExecContext(ctx, cmd,
+    sql.Named("database", database),
+    sql.Named("settings", strings.Join(settings, ", ")),
+  )
+}
+
+// SetDatabaseDefaultFilegroup makes the filegroup the default filegroup of the database, i.e. the filegroup of
+// tables and indexes that are created without one. The filegroup must exist and contain files.
+func (c *Connector) SetDatabaseDefaultFilegroup(ctx context.Context, database, filegroup string) error {
+  cmd := `IF NOT EXISTS (SELECT 1 FROM [sys].[filegroups] WHERE name = @filegroup)
+            BEGIN
+              DECLARE @message nvarchar(2048) = 'filegroup [' + @filegroup + '] does not exist';
+              THROW 50000, @message, 1;
+            END
+          IF NOT EXISTS (SELECT 1 FROM [sys].[filegroups] WHERE name = @filegroup AND is_default = 1)
+            BEGIN
+              DECLARE @sql nvarchar(max) = 'ALTER DATABASE ' + QuoteName(@database) + ' MODIFY FILEGROUP ' + QuoteName(@filegroup) + ' DEFAULT'
+              EXEC (@sql)
+            END`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("database", database), sql.Named("filegroup", filegroup))
+}
+
+// SetDatabaseFilestream sets the FILESTREAM options of the database with ALTER DATABASE ... SET FILESTREAM. An
+// empty access or directory name leaves that option as it is. It fails before anything is changed on editions
+// that do not support FILESTREAM.
+func (c *Connector) SetDatabaseFilestream(ctx context.Context, database, access, directory string) error {
+  if access != "" && !containsString(filestreamAccessValues, access) {
+    return fmt.Errorf("invalid value [%s] of FILESTREAM option [NON_TRANSACTED_ACCESS]", access)
+  }
+  if access == "" && directory == "" {
+    return nil
+  }
+
+  c.setDatabase(&database)
+  edition, err := c.GetEngineEdition(ctx)
+  if err != nil {
+    return err
+  }
+  if !supportsDatabaseOption(edition, "FILESTREAM") {
+    return fmt.Errorf("FILESTREAM is not supported on this edition of SQL Server, only on %s", databaseOptionEditions["FILESTREAM"].description)
+  }
+
+  var settings []string
+  if access != "" {
+    settings = append(settings, "NON_TRANSACTED_ACCESS = "+access)
+  }
+  if directory != "" {
+    settings = append(settings, "DIRECTORY_NAME = N'"+strings.ReplaceAll(directory, "'", "''")+"'")
+  }
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'ALTER DATABASE ' + QuoteName(@database) + ' SET FILESTREAM (' + @settings + ')'
+          EXEC (@sql)`
+  return c.ExecContext(ctx, cmd,
+    sql.Named("database", database),
+    sql.Named("settings", strings.Join(settings, ", ")),
+  )
+}
+
+func supportsDatabaseOption(edition int, name string) bool {
+  supported, ok := databaseOptionEditions[name]
+  if !ok {
+    return true
+  }
+  for _, e := range supported.editions {
+    if e == edition {
+      return true
+    }
+  }
+  return false
+}
+
+func containsString(values []string, value string) bool {
+  for _, v := range values {
+    if v == value {
+      return true
+    }
+  }
+  return false
+}
@@ -0,0 +1,57 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+)
+
+// GetDatabaseState returns the state of the database as READ_WRITE, READ_ONLY or OFFLINE, and whether a
+// state change is still in progress. The state is empty if the database does not exist.
+func (c *Connector) GetDatabaseState(ctx context.Context, database string) (string, bool, error) {
+  cmd := `SELECT CASE WHEN state_desc = 'OFFLINE' THEN 'OFFLINE' WHEN is_read_only = 1 THEN 'READ_ONLY' ELSE 'READ_WRITE' END,
+                 CASE WHEN state_desc IN ('ONLINE', 'OFFLINE') THEN 0 ELSE 1 END
+          FROM [sys].[databases] WHERE name = @database`
+  var state string
+  var pending bool
+  master := "master"
+  err := c.
+    setDatabase(&master).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&state, &pending)
+      },
+      sql.Named("database", database),
+    )
+  if err == sql.ErrNoRows {
+    return "", false, nil
+  }
+  return state, pending, err
+}
+
+// SetDatabaseState changes the state of the database, bringing it online first when needed. Unless
+// rollback is empty, open transactions are rolled back IMMEDIATE, after the given number of seconds,
+// or the change fails at once with NO_WAIT.
+func (c *Connector) SetDatabaseState(ctx context.Context, database, state, rollback string) error {
+  cmd := `DECLARE @sql nvarchar(max) = ''
+          DECLARE @with nvarchar(100) = CASE WHEN @rollback = '' THEN ''
+                                             WHEN @rollback = 'IMMEDIATE' THEN ' WITH ROLLBACK IMMEDIATE'
+                                             WHEN @rollback = 'NO_WAIT' THEN ' WITH NO_WAIT'
+                                             ELSE ' WITH ROLLBACK AFTER ' + CAST(CAST(@rollback AS int) AS nvarchar(10)) + ' SECONDS' END
+          IF @state = 'OFFLINE'
+            SET @sql = 'ALTER DATABASE ' + QuoteName(@database) + ' SET OFFLINE' + @with
+          ELSE
+            BEGIN
+              IF EXISTS (SELECT 1 FROM [sys].[databases] WHERE name = @database AND state_desc = 'OFFLINE')
+                SET @sql = 'ALTER DATABASE ' + QuoteName(@database) + ' SET ONLINE;'
+              SET @sql = @sql + 'ALTER DATABASE ' + QuoteName(@database) + ' SET ' + CASE WHEN @state = 'READ_ONLY' THEN 'READ_ONLY' ELSE 'READ_WRITE' END + @with
+            END
+          EXEC (@sql)`
+  master := "master"
+  return c.
+    setDatabase(&master).
+    ExecContext(ctx, cmd,
+      sql.Named("database", database),
+      sql.Named("state", state),
+      sql.Named("rollback", rollback),
+    )
+}
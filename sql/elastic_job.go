@@ -0,0 +1,102 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+// GetElasticJobTargetGroup reads the target group and its members from the job database, or returns nil if
+// the target group does not exist.
+func (c *Connector) GetElasticJobTargetGroup(ctx context.Context, database, name string) (*model.ElasticJobTargetGroup, error) {
+  cmd := `SELECT target_group_name FROM [jobs].[target_groups] WHERE target_group_name = @name`
+  var group model.ElasticJobTargetGroup
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&group.Name)
+      },
+      sql.Named("name", name),
+    )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+
+  cmd = `SELECT CAST(target_id AS nvarchar(36)), membership_type, target_type, COALESCE(server_name, ''), COALESCE(database_name, ''),
+                COALESCE(elastic_pool_name, ''), COALESCE(refresh_credential_name, '')
+         FROM [jobs].[target_group_members]
+         WHERE target_group_name = @name`
+  group.Members = make([]model.ElasticJobTargetGroupMember, 0)
+  err = c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+    for r.Next() {
+      var member model.ElasticJobTargetGroupMember
+      if err := r.Scan(&member.TargetID, &member.MembershipType, &member.TargetType, &member.ServerName, &member.DatabaseName,
+        &member.ElasticPoolName, &member.RefreshCredentialName); err != nil {
+        return err
+      }
+      group.Members = append(group.Members, member)
+    }
+    return r.Err()
+  }, sql.Named("name", name))
+  if err != nil {
+    return nil, err
+  }
+  return &group, nil
+}
+
+func (c *Connector) CreateElasticJobTargetGroup(ctx context.Context, database, name string) error {
+  cmd := `IF OBJECT_ID('[jobs].[sp_add_target_group]') IS NULL
+            THROW 50000, 'The database is not an elastic job database', 1;
+          EXEC [jobs].[sp_add_target_group] @target_group_name = @name`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("name", name))
+}
+
+func (c *Connector) AddElasticJobTargetGroupMember(ctx context.Context, database, name string, member model.ElasticJobTargetGroupMember) error {
+  cmd := `EXEC [jobs].[sp_add_target_group_member]
+            @target_group_name = @name,
+            @membership_type = @membershipType,
+            @target_type = @targetType,
+            @refresh_credential_name = @refreshCredentialName,
+            @server_name = @serverName,
+            @database_name = @databaseName,
+            @elastic_pool_name = @elasticPoolName`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("name", name),
+      sql.Named("membershipType", member.MembershipType),
+      sql.Named("targetType", member.TargetType),
+      sql.Named("refreshCredentialName", nullIfEmpty(member.RefreshCredentialName)),
+      sql.Named("serverName", member.ServerName),
+      sql.Named("databaseName", nullIfEmpty(member.DatabaseName)),
+      sql.Named("elasticPoolName", nullIfEmpty(member.ElasticPoolName)),
+    )
+}
+
+func (c *Connector) DeleteElasticJobTargetGroupMember(ctx context.Context, database, name, targetID string) error {
+  cmd := `EXEC [jobs].[sp_delete_target_group_member] @target_group_name = @name, @target_id = @targetId`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("name", name), sql.Named("targetId", targetID))
+}
+
+func (c *Connector) DeleteElasticJobTargetGroup(ctx context.Context, database, name string) error {
+  cmd := `IF EXISTS (SELECT 1 FROM [jobs].[target_groups] WHERE target_group_name = @name)
+            EXEC [jobs].[sp_delete_target_group] @target_group_name = @name`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("name", name))
+}
+
+func nullIfEmpty(s string) interface{} {
+  if s == "" {
+    return nil
+  }
+  return s
+}
@@ -0,0 +1,60 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+// GetInstance reads instance-level properties. Properties that are not available on the edition, e.g. the
+// default paths on Azure SQL Database, are returned empty, and services are only listed for SQL Server.
+func (c *Connector) GetInstance(ctx context.Context) (*model.Instance, error) {
+  cmd := `SELECT COALESCE(CAST(SERVERPROPERTY('ServerName') AS nvarchar(256)), ''),
+                 COALESCE(CAST(SERVERPROPERTY('MachineName') AS nvarchar(256)), ''),
+                 COALESCE(CAST(SERVERPROPERTY('InstanceName') AS nvarchar(256)), ''),
+                 COALESCE(CAST(SERVERPROPERTY('Edition') AS nvarchar(256)), ''),
+                 COALESCE(CAST(SERVERPROPERTY('EngineEdition') AS int), 0),
+                 COALESCE(CAST(SERVERPROPERTY('ProductVersion') AS nvarchar(256)), ''),
+                 COALESCE(CAST(SERVERPROPERTY('Collation') AS nvarchar(256)), ''),
+                 COALESCE(CAST(SERVERPROPERTY('IsClustered') AS bit), 0),
+                 COALESCE(CAST(SERVERPROPERTY('IsHadrEnabled') AS bit), 0),
+                 COALESCE(CAST(SERVERPROPERTY('InstanceDefaultDataPath') AS nvarchar(4000)), ''),
+                 COALESCE(CAST(SERVERPROPERTY('InstanceDefaultLogPath') AS nvarchar(4000)), '')`
+  instance := model.Instance{Services: make([]model.InstanceService, 0)}
+  database := "master"
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&instance.ServerName, &instance.MachineName, &instance.InstanceName, &instance.Edition, &instance.EngineEdition,
+          &instance.ProductVersion, &instance.Collation, &instance.IsClustered, &instance.IsHadrEnabled, &instance.DefaultDataPath, &instance.DefaultLogPath)
+      },
+    )
+  if err != nil {
+    return nil, err
+  }
+
+  // sys.dm_server_services is not available on Azure SQL Database (5), Azure SQL Managed Instance (8) and
+  // Azure Synapse Analytics (6, 11).
+  switch instance.EngineEdition {
+  case 5, 6, 8, 11:
+    return &instance, nil
+  }
+  cmd = `SELECT servicename, COALESCE(service_account, ''), COALESCE(status_desc, '')
+         FROM [sys].[dm_server_services]
+         ORDER BY servicename`
+  err = c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+    for r.Next() {
+      var service model.InstanceService
+      if err := r.Scan(&service.Name, &service.ServiceAccount, &service.Status); err != nil {
+        return err
+      }
+      instance.Services = append(instance.Services, service)
+    }
+    return r.Err()
+  })
+  if err != nil {
+    return nil, err
+  }
+  return &instance, nil
+}
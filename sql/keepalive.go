@@ -0,0 +1,61 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "fmt"
+  "github.com/pkg/errors"
+  "log"
+  "sync"
+  "time"
+)
+
+// KeepAlive periodically runs SELECT 1 in every database the connectors have connected to, so that Azure SQL
+// Database serverless databases do not auto-pause during a long apply. It stops when its context is done.
+type KeepAlive struct {
+  ctx      context.Context
+  interval time.Duration
+  mu       sync.Mutex
+  tracked  map[string]bool
+}
+
+func NewKeepAlive(ctx context.Context, interval time.Duration) *KeepAlive {
+  return &KeepAlive{ctx: ctx, interval: interval, tracked: make(map[string]bool)}
+}
+
+// track starts keeping the database of c alive, unless it already is.
+func (k *KeepAlive) track(c *Connector) {
+  if k == nil {
+    return
+  }
+  key := fmt.Sprintf("%s:%s/%s", c.Host, c.Port, c.Database)
+  k.mu.Lock()
+  defer k.mu.Unlock()
+  if k.tracked[key] || k.ctx.Err() != nil {
+    return
+  }
+  k.tracked[key] = true
+  // the connector is copied, as its database changes with the operations of the resource using it
+  conn := *c
+  conn.KeepAlive = nil
+  go k.run(&conn, key)
+}
+
+func (k *KeepAlive) run(c *Connector, key string) {
+  ticker := time.NewTicker(k.interval)
+  defer ticker.Stop()
+  for {
+    select {
+    case <-k.ctx.Done():
+      return
+    case <-ticker.C:
+      err := c.QueryRowContext(k.ctx, "SELECT 1", func(r *sql.Row) error {
+        var one int
+        return r.Scan(&one)
+      })
+      if err != nil && k.ctx.Err() == nil {
+        log.Println(errors.Wrapf(err, "keepalive of [%s] failed", key))
+      }
+    }
+  }
+}
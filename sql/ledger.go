@@ -0,0 +1,60 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+  mssql "github.com/microsoft/go-mssqldb"
+  "github.com/pkg/errors"
+)
+
+// GetDatabaseLedger reads whether database is a ledger database, and where its digests are stored. Servers
+// before SQL Server 2022 have no ledger, so their databases are reported as not being ledger databases.
+func (c *Connector) GetDatabaseLedger(ctx context.Context, database string) (*model.DatabaseLedger, error) {
+  cmd := `DECLARE @ledger bit = 0
+          IF COL_LENGTH('sys.databases', 'is_ledger_on') IS NOT NULL
+            EXEC sp_executesql N'SELECT @ledger = is_ledger_on FROM [sys].[databases] WHERE database_id = DB_ID()', N'@ledger bit OUTPUT', @ledger OUTPUT
+          SELECT @ledger`
+  ledger := model.DatabaseLedger{DigestLocations: make([]model.LedgerDigestLocation, 0)}
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd, func(r *sql.Row) error {
+      return r.Scan(&ledger.IsLedgerOn)
+    })
+  if err != nil || !ledger.IsLedgerOn {
+    return &ledger, err
+  }
+  cmd = `SELECT path, COALESCE(last_digest_block_id, -1), is_current
+         FROM [sys].[database_ledger_digest_locations]
+         ORDER BY path`
+  err = c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+    for r.Next() {
+      var location model.LedgerDigestLocation
+      if err := r.Scan(&location.Path, &location.LastDigestBlockID, &location.IsCurrent); err != nil {
+        return err
+      }
+      ledger.DigestLocations = append(ledger.DigestLocations, location)
+    }
+    return r.Err()
+  })
+  if err != nil {
+    return nil, err
+  }
+  return &ledger, nil
+}
+
+// VerifyDatabaseLedger verifies the ledger of database against the digests in all its digest locations, and
+// returns why the verification failed, or an empty string if it passed. Errors that do not come from the
+// verification, e.g. missing permissions, are returned as errors.
+func (c *Connector) VerifyDatabaseLedger(ctx context.Context, database string) (string, error) {
+  cmd := `DECLARE @locations nvarchar(max) = (SELECT * FROM [sys].[database_ledger_digest_locations] FOR JSON AUTO, INCLUDE_NULL_VALUES)
+          EXEC [sys].[sp_verify_database_ledger_from_digest_storage] @locations`
+  err := c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd)
+  var sqlErr mssql.Error
+  if err != nil && errors.As(err, &sqlErr) && !permissionErrors[sqlErr.Number] {
+    return sqlErr.Message, nil
+  }
+  return "", err
+}
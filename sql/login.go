@@ -4,14 +4,17 @@ import (
   "context"
   "database/sql"
   "github.com/betr-io/terraform-provider-mssql/mssql/model"
+  "github.com/pkg/errors"
 )
 
 func (c *Connector) GetLogin(ctx context.Context, name string) (*model.Login, error) {
   var login model.Login
   err := c.QueryRowContext(ctx,
-    "SELECT principal_id, name, default_database_name, default_language_name FROM [master].[sys].[sql_logins] WHERE [name] = @name",
+    `SELECT p.principal_id, p.name, CASE p.type WHEN 'S' THEN 'SQL' WHEN 'E' THEN 'EXTERNAL' WHEN 'X' THEN 'EXTERNAL' ELSE 'WINDOWS' END, COALESCE(p.default_database_name, ''), COALESCE(p.default_language_name, ''), COALESCE(CONVERT(VARCHAR(514), LOGINPROPERTY(p.name, 'PasswordHash'), 1), ''), CONVERT(VARCHAR(1000), p.sid, 1), COALESCE(l.is_policy_checked, 1), COALESCE(l.is_expiration_checked, 0), CONVERT(VARCHAR(33), p.modify_date, 126), COALESCE(@@SERVERNAME, CAST(SERVERPROPERTY('ServerName') AS nvarchar(128)))
+     FROM [master].[sys].[server_principals] p LEFT JOIN [master].[sys].[sql_logins] l ON l.principal_id = p.principal_id
+     WHERE p.[name] = @name AND p.type IN ('S', 'U', 'G', 'E', 'X')`,
     func(r *sql.Row) error {
-      return r.Scan(&login.PrincipalID, &login.LoginName, &login.DefaultDatabase, &login.DefaultLanguage)
+      return r.Scan(&login.PrincipalID, &login.LoginName, &login.LoginType, &login.DefaultDatabase, &login.DefaultLanguage, &login.PasswordHash, &login.SIDStr, &login.CheckPolicy, &login.CheckExpiration, &login.ModifyDate, &login.ServerName)
     },
     sql.Named("name", name),
   )
@@ -24,58 +27,204 @@ func (c *Connector) GetLogin(ctx context.Context, name string) (*model.Login, er
   return &login, nil
 }
 
-func (c *Connector) CreateLogin(ctx context.Context, name, password, defaultDatabase, defaultLanguage string) error {
+// LoginPasswordMatches compares password with the password hash of the SQL login with PWDCOMPARE. It fails if
+// the hash cannot be read, which requires CONTROL SERVER permission.
+func (c *Connector) LoginPasswordMatches(ctx context.Context, name, password string) (bool, error) {
+  var matches sql.NullBool
+  err := c.QueryRowContext(ctx,
+    `SELECT CAST(PWDCOMPARE(@password, CAST(LOGINPROPERTY(@name, 'PasswordHash') AS varbinary(256))) AS bit)`,
+    func(r *sql.Row) error {
+      return r.Scan(&matches)
+    },
+    sql.Named("name", name),
+    sql.Named("password", password),
+  )
+  if err != nil {
+    return false, err
+  }
+  if !matches.Valid {
+    return false, errors.Errorf("the password hash of login [%s] cannot be read", name)
+  }
+  return matches.Bool, nil
+}
+
+// checkPasswordOptions rejects password options that the server does not support, and password hashes that
+// are not hex strings, as a hash is inserted into the statement without quotes.
+const checkPasswordOptions = `IF @hashed = 1 AND (@password NOT LIKE '0x%' OR SUBSTRING(@password, 3, LEN(@password)) LIKE '%[^0-9A-Fa-f]%')
+                THROW 50000, 'A hashed password must be a password hash as a hex string, e.g. 0x0200...', 1;
+              IF SERVERPROPERTY('EngineEdition') = 5 AND (@hashed = 1 OR @checkPolicy = 0 OR @checkExpiration = 1 OR @mustChange = 1)
+                THROW 50000, 'Azure SQL Database does not support HASHED, MUST_CHANGE, CHECK_POLICY or CHECK_EXPIRATION, as its logins always follow its own password policy', 1;`
+
+// CreateLogin creates the login, and returns the executed statement with the password redacted.
+func (c *Connector) CreateLogin(ctx context.Context, login *model.Login) (string, error) {
   cmd := `DECLARE @sql nvarchar(max)
-          SET @sql = 'CREATE LOGIN ' + QuoteName(@name) + ' ' +
-                     'WITH PASSWORD = ' + QuoteName(@password, '''')
+          DECLARE @options nvarchar(max) = ''
+          DECLARE @passwordValue nvarchar(max) = CASE WHEN @hashed = 1 THEN @password ELSE QuoteName(@password, '''') END
           IF @@VERSION NOT LIKE 'Microsoft SQL Azure%'
             BEGIN
               IF @defaultDatabase = '' SET @defaultDatabase = 'master'
               IF NOT @defaultDatabase = 'master'
                 BEGIN
-                  SET @sql = @sql + ', DEFAULT_DATABASE = ' + QuoteName(@defaultDatabase)
+                  SET @options = @options + ', DEFAULT_DATABASE = ' + QuoteName(@defaultDatabase)
                 END
               DECLARE @serverLanguage nvarchar(max) = (SELECT lang.name FROM [sys].[configurations] c INNER JOIN [sys].[syslanguages] lang ON c.[value] = lang.langid WHERE c.name = 'default language')
               IF NOT @defaultLanguage IN ('', @serverLanguage)
                 BEGIN
-                  SET @sql = @sql + ', DEFAULT_LANGUAGE = ' + QuoteName(@defaultLanguage)
+                  SET @options = @options + ', DEFAULT_LANGUAGE = ' + QuoteName(@defaultLanguage)
                 END
             END
-          EXEC (@sql)`
+          IF @loginType = 'WINDOWS'
+            BEGIN
+              SET @sql = 'CREATE LOGIN ' + QuoteName(@name) + ' FROM WINDOWS'
+              IF @options != '' SET @sql = @sql + ' WITH ' + STUFF(@options, 1, 2, '')
+            END
+          ELSE IF @loginType = 'EXTERNAL'
+            BEGIN
+              IF SERVERPROPERTY('EngineEdition') NOT IN (5, 8) AND CAST(SERVERPROPERTY('ProductMajorVersion') AS int) < 16
+                THROW 50000, 'Logins from an external provider require Azure SQL Managed Instance, Azure SQL Database or SQL Server 2022', 1;
+              SET @sql = 'CREATE LOGIN ' + QuoteName(@name) + ' FROM EXTERNAL PROVIDER'
+              IF @objectId != '' SET @options = ', OBJECT_ID = ' + QuoteName(@objectId, '''') + @options
+              IF @options != '' SET @sql = @sql + ' WITH ' + STUFF(@options, 1, 2, '')
+            END
+          ELSE
+            BEGIN
+              ` + checkPasswordOptions + `
+              SET @sql = 'CREATE LOGIN ' + QuoteName(@name) + ' ' +
+                         'WITH PASSWORD = ' + @passwordValue
+              IF @hashed = 1 SET @sql = @sql + ' HASHED'
+              IF @mustChange = 1 SET @sql = @sql + ' MUST_CHANGE'
+              IF @checkPolicy = 0 SET @options = @options + ', CHECK_POLICY = OFF'
+              IF @checkExpiration = 1 SET @options = @options + ', CHECK_EXPIRATION = ON'
+              SET @sql = @sql + @options
+            END
+          EXEC (@sql)
+          SELECT CASE WHEN @password = '' THEN @sql ELSE REPLACE(@sql, @passwordValue, '''***''') END`
+  var applied string
   database := "master"
-  return c.
+  err := c.
     setDatabase(&database).
-    ExecContext(ctx, cmd,
-    sql.Named("name", name),
-    sql.Named("password", password),
-    sql.Named("defaultDatabase", defaultDatabase),
-    sql.Named("defaultLanguage", defaultLanguage))
+    execQueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&applied)
+    },
+    sql.Named("name", login.LoginName),
+    sql.Named("loginType", login.LoginType),
+    sql.Named("password", login.Password),
+    sql.Named("objectId", login.ObjectId),
+    sql.Named("defaultDatabase", login.DefaultDatabase),
+    sql.Named("defaultLanguage", login.DefaultLanguage),
+    sql.Named("hashed", login.PasswordHashed),
+    sql.Named("checkPolicy", login.CheckPolicy),
+    sql.Named("checkExpiration", login.CheckExpiration),
+    sql.Named("mustChange", login.MustChange))
+  return applied, err
 }
 
-func (c *Connector) UpdateLogin(ctx context.Context, name, password, defaultDatabase, defaultLanguage string) error {
+// UpdateLogin alters the login where it differs, and returns the executed statement with the password
+// redacted, or an empty string if nothing had to change.
+func (c *Connector) UpdateLogin(ctx context.Context, login *model.Login) (string, error) {
   cmd := `DECLARE @sql nvarchar(max)
-          SET @sql = 'ALTER LOGIN ' + QuoteName(@name) + ' ' +
-                     'WITH PASSWORD = ' + QuoteName(@password, '''')
+          DECLARE @options nvarchar(max) = ''
+          DECLARE @passwordValue nvarchar(max) = CASE WHEN @hashed = 1 THEN @password ELSE QuoteName(@password, '''') END
+          IF @loginType = 'SQL'
+            BEGIN
+              ` + checkPasswordOptions + `
+            END
+          IF @password != ''
+            BEGIN
+              SET @options = ', PASSWORD = ' + @passwordValue
+              IF @hashed = 1 SET @options = @options + ' HASHED'
+              IF @mustChange = 1 SET @options = @options + ' MUST_CHANGE'
+            END
+          IF @loginType = 'SQL' AND SERVERPROPERTY('EngineEdition') != 5
+            BEGIN
+              DECLARE @policyChecked bit, @expirationChecked bit
+              SELECT @policyChecked = is_policy_checked, @expirationChecked = is_expiration_checked FROM [master].[sys].[sql_logins] WHERE [name] = @name
+              -- CHECK_EXPIRATION is turned off before CHECK_POLICY, and turned on after it
+              IF @checkExpiration = 0 AND @expirationChecked = 1 SET @options = @options + ', CHECK_EXPIRATION = OFF'
+              IF @checkPolicy != @policyChecked SET @options = @options + ', CHECK_POLICY = ' + CASE WHEN @checkPolicy = 1 THEN 'ON' ELSE 'OFF' END
+              IF @checkExpiration = 1 AND @expirationChecked = 0 SET @options = @options + ', CHECK_EXPIRATION = ON'
+            END
           IF @@VERSION NOT LIKE 'Microsoft SQL Azure%'
             BEGIN
               IF @defaultDatabase = '' SET @defaultDatabase = 'master'
-              IF NOT @defaultDatabase IN (SELECT default_database_name FROM [master].[sys].[sql_logins] WHERE [name] = @name)
+              IF NOT @defaultDatabase IN (SELECT default_database_name FROM [master].[sys].[server_principals] WHERE [name] = @name)
                 BEGIN
-                  SET @sql = @sql + ', DEFAULT_DATABASE = ' + QuoteName(@defaultDatabase)
+                  SET @options = @options + ', DEFAULT_DATABASE = ' + QuoteName(@defaultDatabase)
                 END
                 DECLARE @language nvarchar(max) = @defaultLanguage
               IF @language = '' SET @language = (SELECT lang.name FROM [sys].[configurations] c INNER JOIN [sys].[syslanguages] lang ON c.[value] = lang.langid WHERE c.name = 'default language')
-              IF @language != (SELECT default_language_name FROM [master].[sys].[sql_logins] WHERE [name] = @name)
+              IF @language != (SELECT default_language_name FROM [master].[sys].[server_principals] WHERE [name] = @name)
                 BEGIN
-                  SET @sql = @sql + ', DEFAULT_LANGUAGE = ' + QuoteName(@language)
+                  SET @options = @options + ', DEFAULT_LANGUAGE = ' + QuoteName(@language)
                 END
               END
-          EXEC (@sql)`
-  return c.ExecContext(ctx, cmd,
+          IF @options != ''
+            BEGIN
+              SET @sql = 'ALTER LOGIN ' + QuoteName(@name) + ' WITH ' + STUFF(@options, 1, 2, '')
+              EXEC (@sql)
+            END
+          SELECT CASE WHEN @password = '' THEN COALESCE(@sql, '') ELSE REPLACE(COALESCE(@sql, ''), @passwordValue, '''***''') END`
+  var applied string
+  err := c.execQueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&applied)
+    },
+    sql.Named("name", login.LoginName),
+    sql.Named("loginType", login.LoginType),
+    sql.Named("password", login.Password),
+    sql.Named("defaultDatabase", login.DefaultDatabase),
+    sql.Named("defaultLanguage", login.DefaultLanguage),
+    sql.Named("hashed", login.PasswordHashed),
+    sql.Named("checkPolicy", login.CheckPolicy),
+    sql.Named("checkExpiration", login.CheckExpiration),
+    sql.Named("mustChange", login.MustChange))
+  return applied, err
+}
+
+// LoginHasDatabaseAccess reports whether the login can connect to the database, through a user mapped
+// to its SID, through the guest user, or as a member of sysadmin. Access granted through Windows group
+// membership cannot be detected, and Azure SQL Database and Azure Synapse Analytics always report access.
+func (c *Connector) LoginHasDatabaseAccess(ctx context.Context, database, name string) (bool, error) {
+  cmd := `SELECT CASE WHEN @@VERSION LIKE 'Microsoft SQL Azure%' OR @@VERSION LIKE 'Microsoft Azure SQL Data Warehouse%'
+                        OR IS_SRVROLEMEMBER('sysadmin', @name) = 1
+                        OR EXISTS (SELECT 1 FROM [sys].[database_principals] WHERE [sid] = SUSER_SID(@name))
+                        OR EXISTS (SELECT 1 FROM [sys].[database_permissions]
+                                   WHERE [grantee_principal_id] = DATABASE_PRINCIPAL_ID('guest') AND [type] = 'CO' AND [state] IN ('G', 'W'))
+                      THEN 1 ELSE 0 END`
+  var access bool
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&access)
+    },
     sql.Named("name", name),
-    sql.Named("password", password),
-    sql.Named("defaultDatabase", defaultDatabase),
-    sql.Named("defaultLanguage", defaultLanguage))
+  )
+  return access, err
+}
+
+// VerifyLoginAccess impersonates the login and returns the access it is missing, i.e. CONNECT SQL permission
+// on the server, and access to its default database.
+func (c *Connector) VerifyLoginAccess(ctx context.Context, name, defaultDatabase string) ([]string, error) {
+  cmd := `DECLARE @problems TABLE (problem nvarchar(max))
+          EXECUTE AS LOGIN = @name
+          BEGIN TRY
+            IF ISNULL(HAS_PERMS_BY_NAME(NULL, NULL, 'CONNECT SQL'), 0) = 0
+              INSERT @problems VALUES ('no CONNECT SQL permission on server')
+            IF @defaultDatabase != '' AND ISNULL(HAS_DBACCESS(@defaultDatabase), 0) = 0
+              INSERT @problems VALUES ('no access to default database ' + QuoteName(@defaultDatabase))
+          END TRY
+          BEGIN CATCH
+            REVERT;
+            THROW;
+          END CATCH
+          REVERT
+          SELECT problem FROM @problems`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    queryProblems(ctx, cmd, sql.Named("name", name), sql.Named("defaultDatabase", defaultDatabase))
 }
 
 func (c *Connector) DeleteLogin(ctx context.Context, name string) error {
@@ -83,7 +232,7 @@ func (c *Connector) DeleteLogin(ctx context.Context, name string) error {
     return err
   }
   cmd := `DECLARE @sql nvarchar(max)
-          SET @sql = 'IF EXISTS (SELECT 1 FROM [master].[sys].[sql_logins] WHERE [name] = ' + QuoteName(@name, '''') + ') ' +
+          SET @sql = 'IF EXISTS (SELECT 1 FROM [master].[sys].[server_principals] WHERE [name] = ' + QuoteName(@name, '''') + ') ' +
                      'DROP LOGIN ' + QuoteName(@name)
           EXEC (@sql)`
   return c.ExecContext(ctx, cmd, sql.Named("name", name))
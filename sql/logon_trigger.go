@@ -0,0 +1,78 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+func (c *Connector) GetLogonTrigger(ctx context.Context, name string) (*model.LogonTrigger, error) {
+  cmd := `SELECT t.name, t.is_disabled, COALESCE(m.definition, '')
+          FROM [sys].[server_triggers] t
+            LEFT JOIN [sys].[server_sql_modules] m ON t.object_id = m.object_id
+          WHERE t.name = @name`
+  var trigger model.LogonTrigger
+  database := "master"
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&trigger.Name, &trigger.IsDisabled, &trigger.Definition)
+      },
+      sql.Named("name", name),
+    )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+  return &trigger, nil
+}
+
+// SetLogonTrigger creates or alters a logon trigger that rolls back logons of the login when condition is
+// false. The condition is inserted verbatim and may refer to @ip, the client IPv4 address as a number (NULL
+// for other addresses, so the condition must not evaluate to unknown), and @minute, the minute of the day in
+// server local time. Logons from the local machine are always allowed, so that a misconfigured trigger can be
+// disabled.
+func (c *Connector) SetLogonTrigger(ctx context.Context, name, loginName, condition string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = CASE WHEN EXISTS (SELECT 1 FROM [sys].[server_triggers] WHERE name = @name) THEN 'ALTER' ELSE 'CREATE' END +
+                     ' TRIGGER ' + QuoteName(@name) + ' ON ALL SERVER FOR LOGON AS ' +
+                     'BEGIN ' +
+                     '  IF ORIGINAL_LOGIN() = ' + QuoteName(@loginName, '''') + ' ' +
+                     '  BEGIN ' +
+                     '    DECLARE @host nvarchar(128) = EVENTDATA().value(''(/EVENT_INSTANCE/ClientHost)[1]'', ''nvarchar(128)''); ' +
+                     '    DECLARE @ip bigint = TRY_CAST(PARSENAME(@host, 4) AS bigint) * 16777216 + TRY_CAST(PARSENAME(@host, 3) AS bigint) * 65536 + ' +
+                     '                         TRY_CAST(PARSENAME(@host, 2) AS bigint) * 256 + TRY_CAST(PARSENAME(@host, 1) AS bigint); ' +
+                     '    DECLARE @minute int = DATEPART(hour, GETDATE()) * 60 + DATEPART(minute, GETDATE()); ' +
+                     '    IF @host != ''<local machine>'' AND NOT (' + @condition + ') ROLLBACK; ' +
+                     '  END ' +
+                     'END'
+          EXEC (@sql)`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("name", name), sql.Named("loginName", loginName), sql.Named("condition", condition))
+}
+
+func (c *Connector) SetLogonTriggerEnabled(ctx context.Context, name string, enabled bool) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = CASE WHEN @enabled = 1 THEN 'ENABLE' ELSE 'DISABLE' END + ' TRIGGER ' + QuoteName(@name) + ' ON ALL SERVER'
+          EXEC (@sql)`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("name", name), sql.Named("enabled", enabled))
+}
+
+func (c *Connector) DeleteLogonTrigger(ctx context.Context, name string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'IF EXISTS (SELECT 1 FROM [sys].[server_triggers] WHERE [name] = ' + QuoteName(@name, '''') + ') ' +
+                     'DROP TRIGGER ' + QuoteName(@name) + ' ON ALL SERVER'
+          EXEC (@sql)`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("name", name))
+}
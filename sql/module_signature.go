@@ -0,0 +1,81 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+// GetModuleSignatures returns the modules in database signed by the certificate, or nil if the certificate
+// does not exist. Countersignatures are not included.
+func (c *Connector) GetModuleSignatures(ctx context.Context, database, certificate string) (*model.ModuleSignature, error) {
+  cmd := `SELECT c.name, COALESCE(SCHEMA_NAME(o.schema_id) + '.' + o.name, '')
+          FROM [sys].[certificates] c
+            LEFT JOIN [sys].[crypt_properties] cp ON cp.thumbprint = c.thumbprint AND cp.class = 1 AND cp.crypt_type = 'SPVC'
+            LEFT JOIN [sys].[objects] o ON o.object_id = cp.major_id
+          WHERE c.name = @certificate
+          ORDER BY 2`
+  var signature *model.ModuleSignature
+  err := c.
+    setDatabase(&database).
+    QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        var name, module string
+        if err := r.Scan(&name, &module); err != nil {
+          return err
+        }
+        if signature == nil {
+          signature = &model.ModuleSignature{Certificate: name, Modules: make([]string, 0)}
+        }
+        if module != "" {
+          signature.Modules = append(signature.Modules, module)
+        }
+      }
+      return r.Err()
+    },
+      sql.Named("certificate", certificate),
+    )
+  if err != nil {
+    return nil, err
+  }
+  return signature, nil
+}
+
+// AddModuleSignature signs the module with the certificate. The password decrypts the private key of the
+// certificate, and is only needed when the key is not encrypted by the database master key.
+func (c *Connector) AddModuleSignature(ctx context.Context, database, certificate, schemaName, name, password string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'ADD SIGNATURE TO ' + QuoteName(@schemaName) + '.' + QuoteName(@name) + ' BY CERTIFICATE ' + QuoteName(@certificate)
+          IF @password != ''
+            SET @sql = @sql + ' WITH PASSWORD = ' + QuoteName(@password, '''')
+          EXEC (@sql)`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("certificate", certificate),
+      sql.Named("schemaName", schemaName),
+      sql.Named("name", name),
+      sql.Named("password", password),
+    )
+}
+
+// DropModuleSignature drops the signature of the module by the certificate, if it is signed by it.
+func (c *Connector) DropModuleSignature(ctx context.Context, database, certificate, schemaName, name string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          IF EXISTS (SELECT 1
+                     FROM [sys].[crypt_properties] cp
+                       INNER JOIN [sys].[certificates] c ON c.thumbprint = cp.thumbprint
+                     WHERE cp.class = 1 AND cp.crypt_type = 'SPVC' AND c.name = @certificate
+                       AND cp.major_id = OBJECT_ID(QuoteName(@schemaName) + '.' + QuoteName(@name)))
+            BEGIN
+              SET @sql = 'DROP SIGNATURE FROM ' + QuoteName(@schemaName) + '.' + QuoteName(@name) + ' BY CERTIFICATE ' + QuoteName(@certificate)
+              EXEC (@sql)
+            END`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("certificate", certificate),
+      sql.Named("schemaName", schemaName),
+      sql.Named("name", name),
+    )
+}
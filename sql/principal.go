@@ -0,0 +1,71 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+func (c *Connector) GetServerPrincipal(ctx context.Context, name, sid string) (*model.Principal, error) {
+  cmd := `SELECT SUSER_SNAME(s.sid), CONVERT(VARCHAR(1000), s.sid, 1)
+          FROM (SELECT CASE WHEN @name != '' THEN SUSER_SID(@name) ELSE CONVERT(VARBINARY(85), @sid, 1) END AS sid) s
+          WHERE s.sid IS NOT NULL AND SUSER_SNAME(s.sid) IS NOT NULL`
+  var principal model.Principal
+  err := c.QueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&principal.Name, &principal.SIDStr)
+    },
+    sql.Named("name", name),
+    sql.Named("sid", sid),
+  )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+  return &principal, nil
+}
+
+func (c *Connector) GetDatabasePrincipal(ctx context.Context, database, name, sid string) (*model.Principal, error) {
+  cmd := `SELECT name, CONVERT(VARCHAR(1000), sid, 1)
+          FROM [sys].[database_principals]
+          WHERE sid IS NOT NULL AND ((@name != '' AND name = @name) OR (@name = '' AND sid = CONVERT(VARBINARY(85), @sid, 1)))`
+  var principal model.Principal
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&principal.Name, &principal.SIDStr)
+      },
+      sql.Named("name", name),
+      sql.Named("sid", sid),
+    )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+  return &principal, nil
+}
+
+// PrincipalMissing reports whether the login name, or the user name of database if database is set, does not
+// exist. Metadata visibility hides principals the connected principal has no permission on, so the principal
+// is only reported missing with VIEW ANY DEFINITION permission on the server, or VIEW DEFINITION permission on
+// the database.
+func (c *Connector) PrincipalMissing(ctx context.Context, database, name string) (bool, error) {
+  cmd := `SELECT CAST(CASE WHEN @server = 1 THEN CASE WHEN SUSER_ID(@name) IS NULL AND HAS_PERMS_BY_NAME(NULL, NULL, 'VIEW ANY DEFINITION') = 1 THEN 1 ELSE 0 END
+                           ELSE CASE WHEN DATABASE_PRINCIPAL_ID(@name) IS NULL AND HAS_PERMS_BY_NAME(NULL, 'DATABASE', 'VIEW DEFINITION') = 1 THEN 1 ELSE 0 END
+                      END AS bit)`
+  server := database == ""
+  var missing bool
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd, func(r *sql.Row) error {
+      return r.Scan(&missing)
+    },
+    sql.Named("server", server),
+    sql.Named("name", name))
+  return missing, err
+}
@@ -0,0 +1,37 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+)
+
+func (c *Connector) ExecuteStatement(ctx context.Context, database, statement string) error {
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, statement)
+}
+
+func (c *Connector) StatementReturnsRows(ctx context.Context, database, query string) (bool, error) {
+  var found bool
+  err := c.
+    setDatabase(&database).
+    QueryContext(ctx, query, func(r *sql.Rows) error {
+      found = r.Next()
+      return r.Err()
+    })
+  if err != nil {
+    return false, err
+  }
+  return found, nil
+}
+
+// GetDefaultSchema returns the schema unqualified names resolve to for the connecting principal.
+func (c *Connector) GetDefaultSchema(ctx context.Context, database string) (string, error) {
+  var schema string
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, "SELECT SCHEMA_NAME()", func(r *sql.Row) error {
+      return r.Scan(&schema)
+    })
+  return schema, err
+}
@@ -0,0 +1,93 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+func (c *Connector) GetDatabaseRoles(ctx context.Context, database string) ([]string, error) {
+  cmd := `SELECT name FROM [sys].[database_principals] WHERE type = 'R'`
+  var roles []string
+  err := c.
+    setDatabase(&database).
+    QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        var name string
+        if err := r.Scan(&name); err != nil {
+          return err
+        }
+        roles = append(roles, name)
+      }
+      return r.Err()
+    })
+  if err != nil {
+    return nil, err
+  }
+  return roles, nil
+}
+
+func (c *Connector) CreateDatabaseRole(ctx context.Context, database, role string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'IF NOT EXISTS (SELECT 1 FROM [sys].[database_principals] WHERE [name] = ' + QuoteName(@role, '''') + ' AND [type] = ''R'') ' +
+                     'CREATE ROLE ' + QuoteName(@role)
+          EXEC (@sql)`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("role", role))
+}
+
+// GetRoleMembers lists the direct members of role, a server role if database is empty and a database role
+// otherwise, or returns nil if there is no such role. The login name of a database user is resolved from its
+// SID, and is empty for users without a login.
+func (c *Connector) GetRoleMembers(ctx context.Context, database, role string) ([]model.RoleMember, error) {
+  existsCmd := `SELECT principal_id FROM [sys].[database_principals] WHERE [name] = @role AND [type] = 'R'`
+  cmd := `SELECT m.principal_id, m.name, m.type_desc, COALESCE(CONVERT(VARCHAR(1000), m.sid, 1), ''),
+                 CASE WHEN m.type = 'R' THEN '' ELSE COALESCE(SUSER_SNAME(m.sid), '') END,
+                 CAST(CASE WHEN m.type = 'R' THEN 1 ELSE 0 END AS bit)
+          FROM [sys].[database_role_members] rm
+          INNER JOIN [sys].[database_principals] m ON m.principal_id = rm.member_principal_id
+          WHERE rm.role_principal_id = @roleID
+          ORDER BY m.name`
+  if database == "" {
+    database = "master"
+    existsCmd = `SELECT principal_id FROM [sys].[server_principals] WHERE [name] = @role AND [type] = 'R'`
+    cmd = `SELECT m.principal_id, m.name, m.type_desc, COALESCE(CONVERT(VARCHAR(1000), m.sid, 1), ''),
+                  CASE WHEN m.type = 'R' THEN '' ELSE m.name END,
+                  CAST(CASE WHEN m.type = 'R' THEN 1 ELSE 0 END AS bit)
+           FROM [sys].[server_role_members] rm
+           INNER JOIN [sys].[server_principals] m ON m.principal_id = rm.member_principal_id
+           WHERE rm.role_principal_id = @roleID
+           ORDER BY m.name`
+  }
+  var roleID int64
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, existsCmd,
+      func(r *sql.Row) error {
+        return r.Scan(&roleID)
+      },
+      sql.Named("role", role),
+    )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+  members := make([]model.RoleMember, 0)
+  err = c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+    for r.Next() {
+      var member model.RoleMember
+      if err := r.Scan(&member.PrincipalID, &member.Name, &member.TypeDesc, &member.SIDStr, &member.LoginName, &member.IsRole); err != nil {
+        return err
+      }
+      members = append(members, member)
+    }
+    return r.Err()
+  }, sql.Named("roleID", roleID))
+  if err != nil {
+    return nil, err
+  }
+  return members, nil
+}
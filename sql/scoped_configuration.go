@@ -0,0 +1,78 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "fmt"
+  "regexp"
+)
+
+// GetDatabaseMaxDop returns the MAXDOP database scoped configuration, and the value for secondaries, which
+// is PRIMARY when secondaries use the value of the primary.
+func (c *Connector) GetDatabaseMaxDop(ctx context.Context, database string) (int, string, error) {
+  cmd := `SELECT CAST(value AS int), COALESCE(CAST(value_for_secondary AS nvarchar(10)), 'PRIMARY')
+          FROM [sys].[database_scoped_configurations] WHERE name = 'MAXDOP'`
+  var maxDop int
+  var secondary string
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd, func(r *sql.Row) error {
+      return r.Scan(&maxDop, &secondary)
+    })
+  return maxDop, secondary, err
+}
+
+func (c *Connector) SetDatabaseMaxDop(ctx context.Context, database string, maxDop int, secondary string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'ALTER DATABASE SCOPED CONFIGURATION SET MAXDOP = ' + CAST(@maxDop AS nvarchar(10)) + ';' +
+                     'ALTER DATABASE SCOPED CONFIGURATION FOR SECONDARY SET MAXDOP = ' +
+                     CASE WHEN @secondary = 'PRIMARY' THEN 'PRIMARY' ELSE CAST(CAST(@secondary AS int) AS nvarchar(10)) END
+          EXEC (@sql)`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("maxDop", maxDop), sql.Named("secondary", secondary))
+}
+
+// GetDatabaseScopedConfiguration returns the value of the named database scoped configuration. Options that
+// sys.database_scoped_configurations stores as numbers, e.g. IDENTITY_CACHE, are returned as ON or OFF, and those
+// it stores as text, e.g. ELEVATE_ONLINE, as stored. It returns "" if the server does not have the option.
+func (c *Connector) GetDatabaseScopedConfiguration(ctx context.Context, database, name string) (string, error) {
+  cmd := `SELECT CASE WHEN SQL_VARIANT_PROPERTY(value, 'BaseType') IN ('bit', 'tinyint', 'smallint', 'int', 'bigint')
+                      THEN CASE WHEN CAST(value AS bigint) = 0 THEN 'OFF' ELSE 'ON' END
+                      ELSE UPPER(CAST(value AS nvarchar(60)))
+                 END
+          FROM [sys].[database_scoped_configurations] WHERE name = @name`
+  var value string
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd, func(r *sql.Row) error {
+      return r.Scan(&value)
+    },
+    sql.Named("name", name))
+  if err == sql.ErrNoRows {
+    return "", nil
+  }
+  return value, err
+}
+
+// scopedConfigurationWord matches the names and values of named database scoped configurations, which are
+// inserted into the statement.
+var scopedConfigurationWord = regexp.MustCompile(`^[A-Z_]+$`)
+
+// SetDatabaseScopedConfiguration sets the named database scoped configuration to value, e.g. ON or
+// WHEN_SUPPORTED. It fails with a clear error if the server does not have the option.
+func (c *Connector) SetDatabaseScopedConfiguration(ctx context.Context, database, name, value string) error {
+  if !scopedConfigurationWord.MatchString(name) || !scopedConfigurationWord.MatchString(value) {
+    return fmt.Errorf("invalid database scoped configuration [%s = %s]", name, value)
+  }
+  cmd := `IF NOT EXISTS (SELECT 1 FROM [sys].[database_scoped_configurations] WHERE name = @name)
+            BEGIN
+              DECLARE @message nvarchar(2048) = @name + ' is not supported by this version or edition of SQL Server';
+              THROW 50000, @message, 1;
+            END
+          DECLARE @sql nvarchar(max) = 'ALTER DATABASE SCOPED CONFIGURATION SET ' + @name + ' = ' + @value
+          EXEC (@sql)`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("name", name), sql.Named("value", value))
+}
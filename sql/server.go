@@ -0,0 +1,51 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+)
+
+func (c *Connector) GetServerName(ctx context.Context) (string, error) {
+  var name string
+  err := c.QueryRowContext(ctx,
+    "SELECT COALESCE(@@SERVERNAME, CAST(SERVERPROPERTY('ServerName') AS nvarchar(128)))",
+    func(r *sql.Row) error {
+      return r.Scan(&name)
+    },
+  )
+  if err != nil {
+    return "", err
+  }
+  return name, nil
+}
+
+// GetEngineEdition returns SERVERPROPERTY('EngineEdition') of the connected database, e.g. 4 for SQL Server
+// Express, 5 for Azure SQL Database, and 6 or 11 for Azure Synapse Analytics dedicated and serverless SQL pools.
+func (c *Connector) GetEngineEdition(ctx context.Context) (int, error) {
+  var edition int
+  err := c.QueryRowContext(ctx, "SELECT CAST(SERVERPROPERTY('EngineEdition') AS int)", func(r *sql.Row) error {
+    return r.Scan(&edition)
+  })
+  return edition, err
+}
+
+// GetServerVersion returns SERVERPROPERTY('EngineEdition') and SERVERPROPERTY('ProductMajorVersion'), e.g. 16 for
+// SQL Server 2022.
+func (c *Connector) GetServerVersion(ctx context.Context) (int, int, error) {
+  var edition, majorVersion int
+  err := c.QueryRowContext(ctx,
+    "SELECT CAST(SERVERPROPERTY('EngineEdition') AS int), COALESCE(CAST(SERVERPROPERTY('ProductMajorVersion') AS int), 0)",
+    func(r *sql.Row) error {
+      return r.Scan(&edition, &majorVersion)
+    },
+  )
+  return edition, majorVersion, err
+}
+
+func (c *Connector) isSynapse(ctx context.Context) (bool, error) {
+  edition, err := c.GetEngineEdition(ctx)
+  if err != nil {
+    return false, err
+  }
+  return edition == 6 || edition == 11, nil
+}
@@ -0,0 +1,84 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+// GetServerPrincipals lists all logins on the server, excluding server roles and internal certificate
+// logins. Server role memberships and server-level permissions are only read when requested, each in a
+// single query for all logins.
+func (c *Connector) GetServerPrincipals(ctx context.Context, includeRoles, includePermissions bool) ([]*model.ServerPrincipal, error) {
+  cmd := `SELECT principal_id, name, type_desc, CONVERT(VARCHAR(1000), sid, 1), is_disabled, COALESCE(default_database_name, '')
+          FROM [sys].[server_principals]
+          WHERE type != 'R' AND name NOT LIKE '##%##'
+          ORDER BY name`
+  var principals []*model.ServerPrincipal
+  byID := make(map[int64]*model.ServerPrincipal)
+  database := "master"
+  err := c.
+    setDatabase(&database).
+    QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        principal := &model.ServerPrincipal{Roles: make([]string, 0), Permissions: make([]model.ServerPermission, 0)}
+        if err := r.Scan(&principal.PrincipalID, &principal.Name, &principal.Type, &principal.SIDStr, &principal.IsDisabled, &principal.DefaultDatabase); err != nil {
+          return err
+        }
+        principals = append(principals, principal)
+        byID[principal.PrincipalID] = principal
+      }
+      return r.Err()
+    })
+  if err != nil {
+    return nil, err
+  }
+
+  if includeRoles {
+    cmd = `SELECT rm.member_principal_id, r.name
+           FROM [sys].[server_role_members] rm
+           INNER JOIN [sys].[server_principals] r ON r.principal_id = rm.role_principal_id
+           ORDER BY r.name`
+    err = c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        var principalID int64
+        var role string
+        if err := r.Scan(&principalID, &role); err != nil {
+          return err
+        }
+        if principal, ok := byID[principalID]; ok {
+          principal.Roles = append(principal.Roles, role)
+        }
+      }
+      return r.Err()
+    })
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  if includePermissions {
+    cmd = `SELECT grantee_principal_id, permission_name, state_desc
+           FROM [sys].[server_permissions]
+           WHERE class = 100
+           ORDER BY permission_name`
+    err = c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        var principalID int64
+        var permission model.ServerPermission
+        if err := r.Scan(&principalID, &permission.Permission, &permission.State); err != nil {
+          return err
+        }
+        if principal, ok := byID[principalID]; ok {
+          principal.Permissions = append(principal.Permissions, permission)
+        }
+      }
+      return r.Err()
+    })
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  return principals, nil
+}
@@ -0,0 +1,73 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+)
+
+func (c *Connector) CreateDatabaseSnapshot(ctx context.Context, snapshot *model.DatabaseSnapshot) error {
+  // Each data file of the source database gets a sparse file named <snapshot>_<logical name>.ss, placed in
+  // the requested directory or next to the source file. Log files are not part of a snapshot.
+  cmd := `DECLARE @sql nvarchar(max)
+          DECLARE @files nvarchar(max) = ''
+          SELECT @files = @files + ', (NAME = ' + QuoteName(f.name) + ', FILENAME = ''' +
+                          REPLACE(CASE WHEN @directory != '' THEN @directory
+                                       ELSE LEFT(f.physical_name, LEN(f.physical_name) - CHARINDEX(s.sep, REVERSE(f.physical_name))) END +
+                                  s.sep + @name + '_' + f.name + '.ss', '''', '''''') + ''')'
+            FROM [sys].[master_files] f
+              CROSS APPLY (SELECT CASE WHEN CHARINDEX('/', f.physical_name) > 0 THEN '/' ELSE '\' END AS sep) s
+            WHERE f.database_id = DB_ID(@database) AND f.type = 0
+          SET @sql = 'CREATE DATABASE ' + QuoteName(@name) + ' ON ' + STUFF(@files, 1, 2, '') + ' AS SNAPSHOT OF ' + QuoteName(@database)
+          EXEC (@sql)`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("name", snapshot.Name),
+      sql.Named("database", snapshot.Database),
+      sql.Named("directory", snapshot.Directory),
+    )
+}
+
+func (c *Connector) GetDatabaseSnapshot(ctx context.Context, name string) (*model.DatabaseSnapshot, error) {
+  cmd := `SELECT s.name, d.name, CONVERT(VARCHAR(33), s.create_date, 126)
+          FROM [sys].[databases] s
+            INNER JOIN [sys].[databases] d ON s.source_database_id = d.database_id
+          WHERE s.name = @name`
+  var snapshot model.DatabaseSnapshot
+  err := c.QueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&snapshot.Name, &snapshot.Database, &snapshot.CreateDate)
+    },
+    sql.Named("name", name),
+  )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+  return &snapshot, nil
+}
+
+func (c *Connector) DropDatabaseSnapshot(ctx context.Context, name string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'IF EXISTS (SELECT 1 FROM [sys].[databases] WHERE [name] = ' + QuoteName(@name, '''') + ' AND [source_database_id] IS NOT NULL) ' +
+                     'DROP DATABASE ' + QuoteName(@name)
+          EXEC (@sql)`
+  database := "master"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd, sql.Named("name", name))
+}
+
+func (c *Connector) RevertDatabaseSnapshot(ctx context.Context, database, name string) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'RESTORE DATABASE ' + QuoteName(@database) + ' FROM DATABASE_SNAPSHOT = ' + QuoteName(@name, '''')
+          EXEC (@sql)`
+  master := "master"
+  return c.
+    setDatabase(&master).
+    ExecContext(ctx, cmd, sql.Named("database", database), sql.Named("name", name))
+}
@@ -2,21 +2,33 @@ package sql
 
 import (
 	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"net/url"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/go-autorest/autorest/adal"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/betr-io/terraform-provider-mssql/mssql/model"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	mssql "github.com/microsoft/go-mssqldb"
 	"github.com/microsoft/go-mssqldb/azuread"
+	"github.com/microsoft/go-mssqldb/msdsn"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 )
 
 type factory struct{}
@@ -36,6 +48,22 @@ func (f factory) GetConnector(prefix string, data *schema.ResourceData) (interfa
     Timeout: data.Timeout(schema.TimeoutRead),
   }
 
+  if v, ok := data.GetOk(prefix + "host_name_in_certificate"); ok {
+    connector.HostNameInCertificate = v.(string)
+  }
+
+  if v, ok := data.GetOk(prefix + "tls_min_version"); ok {
+    connector.TLSMinVersion = v.(string)
+  }
+
+  if v, ok := data.GetOk(prefix + "server_certificate_thumbprint"); ok {
+    connector.ServerCertificateThumbprint = v.(string)
+  }
+
+  if v, ok := data.GetOk(prefix + "column_encryption"); ok {
+    connector.ColumnEncryption = v.(string) == "Enabled"
+  }
+
   if admin, ok := data.GetOk(prefix + "login.0"); ok {
     admin := admin.(map[string]interface{})
     connector.Login = &LoginUser{
@@ -51,6 +79,9 @@ func (f factory) GetConnector(prefix string, data *schema.ResourceData) (interfa
       ClientID:     admin["client_id"].(string),
       ClientSecret: admin["client_secret"].(string),
     }
+    if environment, ok := admin["environment"].(string); ok {
+      connector.AzureLogin.Environment = environment
+    }
   }
 
   if admin, ok := data.GetOk(prefix + "azuread_managed_identity_auth.0"); ok {
@@ -60,18 +91,50 @@ func (f factory) GetConnector(prefix string, data *schema.ResourceData) (interfa
     }
   }
 
+  if admin, ok := data.GetOk(prefix + "azuread_interactive_auth.0"); ok {
+    admin := admin.(map[string]interface{})
+    connector.FedauthInteractive = &FedauthInteractive{
+      TenantID:    admin["tenant_id"].(string),
+      ClientID:    admin["client_id"].(string),
+      DeviceCode:  admin["device_code"].(bool),
+      Environment: admin["environment"].(string),
+    }
+  }
+
   return connector, nil
 }
 
 type Connector struct {
-  Host       string `json:"host"`
-  Port       string `json:"port"`
-  Database   string `json:"database"`
-  Login      *LoginUser
-  AzureLogin *AzureLogin
-  FedauthMSI *FedauthMSI
-  Timeout    time.Duration `json:"timeout,omitempty"`
-  Token      string
+  Host               string `json:"host"`
+  Port               string `json:"port"`
+  Database           string `json:"database"`
+  Login              *LoginUser
+  AzureLogin         *AzureLogin
+  FedauthMSI         *FedauthMSI
+  FedauthInteractive *FedauthInteractive
+  Timeout            time.Duration `json:"timeout,omitempty"`
+  Token              string
+  // ColumnEncryption enables Always Encrypted support in the driver
+  ColumnEncryption bool
+  // HostNameInCertificate is the host name expected in the server certificate, when it differs from Host
+  HostNameInCertificate string
+  // TLSMinVersion lowers the minimum TLS version accepted, for servers that do not support TLS 1.2
+  TLSMinVersion string
+  // ServerCertificateThumbprint is the SHA-1 or SHA-256 thumbprint, as hex, that the server certificate must have
+  ServerCertificateThumbprint string
+  // AdditionalTransientErrors are numbers of SQL errors to retry in addition to transientErrors
+  AdditionalTransientErrors []int32
+  // SessionLanguage and SessionDateFormat are set on every session, regardless of the defaults of the login
+  SessionLanguage   string
+  SessionDateFormat string
+  // ExecuteAsType is LOGIN or USER, and ExecuteAsName the principal every session impersonates, if set
+  ExecuteAsType string
+  ExecuteAsName string
+  // sessionChecked is set once sessions in sessionCheckedDatabase are known to use SessionLanguage and ExecuteAsName
+  sessionChecked         bool
+  sessionCheckedDatabase string
+  // KeepAlive keeps the databases the connector connects to from pausing, if set
+  KeepAlive *KeepAlive
 }
 
 type LoginUser struct {
@@ -83,17 +146,26 @@ type AzureLogin struct {
   TenantID     string `json:"tenant_id,omitempty"`
   ClientID     string `json:"client_id,omitempty"`
   ClientSecret string `json:"client_secret,omitempty"`
+  Environment  string `json:"environment,omitempty"`
 }
 
 type FedauthMSI struct {
   UserID string `json:"user_id,omitempty"`
 }
 
+type FedauthInteractive struct {
+  TenantID    string `json:"tenant_id,omitempty"`
+  ClientID    string `json:"client_id,omitempty"`
+  DeviceCode  bool   `json:"device_code,omitempty"`
+  Environment string `json:"environment,omitempty"`
+}
+
 func (c *Connector) PingContext(ctx context.Context) error {
   db, err := c.db()
   if err != nil {
     return err
   }
+  defer db.Close()
 
   err = db.PingContext(ctx)
   if err != nil {
@@ -103,8 +175,11 @@ func (c *Connector) PingContext(ctx context.Context) error {
   return nil
 }
 
-// Execute an SQL statement and ignore the results
+// Execute an SQL statement and ignore the results. The statement may not be idempotent, so it is not retried on
+// transient errors once sent, e.g. a deadlock may have rolled back only part of a batch. Connecting is retried by
+// connectLoop.
 func (c *Connector) ExecContext(ctx context.Context, command string, args ...interface{}) error {
+  defer c.logDuration(ctx, "exec", time.Now())
   db, err := c.db()
   if err != nil {
     return err
@@ -113,46 +188,206 @@ func (c *Connector) ExecContext(ctx context.Context, command string, args ...int
 
   _, err = db.ExecContext(ctx, command, args...)
   if err != nil {
-    return err
+    return withPrincipal(ctx, db, err)
   }
 
   return nil
 }
 
+// QueryContext runs the query and passes the rows to scanner. Only running the query is retried on transient
+// errors, as the scanner may already have consumed some of the rows.
 func (c *Connector) QueryContext(ctx context.Context, query string, scanner func(*sql.Rows) error, args ...interface{}) error {
-  db, err := c.db()
-  if err != nil {
-    return err
-  }
-  defer db.Close()
+  return c.queryContext(ctx, c.retryTransient, query, scanner, args...)
+}
 
-  rows, err := db.QueryContext(ctx, query, args...)
+// execQueryContext is QueryContext for statements that change the server and return rows, which like
+// ExecContext are not retried.
+func (c *Connector) execQueryContext(ctx context.Context, query string, scanner func(*sql.Rows) error, args ...interface{}) error {
+  return c.queryContext(ctx, runOnce, query, scanner, args...)
+}
+
+func (c *Connector) queryContext(ctx context.Context, retry func(context.Context, func() error) error, query string, scanner func(*sql.Rows) error, args ...interface{}) error {
+  defer c.logDuration(ctx, "query", time.Now())
+  var (
+    db   *sql.DB
+    rows *sql.Rows
+  )
+  err := retry(ctx, func() (err error) {
+    db, err = c.db()
+    if err != nil {
+      return err
+    }
+    rows, err = db.QueryContext(ctx, query, args...)
+    if err != nil {
+      err = withPrincipal(ctx, db, err)
+      db.Close()
+      return err
+    }
+    return nil
+  })
   if err != nil {
     return err
   }
+  defer db.Close()
   defer rows.Close()
 
   err = scanner(rows)
   if err != nil {
-    return err
+    return withPrincipal(ctx, db, err)
   }
 
   return nil
 }
 
 func (c *Connector) QueryRowContext(ctx context.Context, query string, scanner func(*sql.Row) error, args ...interface{}) error {
-  db, err := c.db()
-  if err != nil {
-    return err
+  return c.queryRowContext(ctx, c.retryTransient, query, scanner, args...)
+}
+
+// execQueryRowContext is QueryRowContext for statements that change the server and return a row, which like
+// ExecContext are not retried.
+func (c *Connector) execQueryRowContext(ctx context.Context, query string, scanner func(*sql.Row) error, args ...interface{}) error {
+  return c.queryRowContext(ctx, runOnce, query, scanner, args...)
+}
+
+func (c *Connector) queryRowContext(ctx context.Context, retry func(context.Context, func() error) error, query string, scanner func(*sql.Row) error, args ...interface{}) error {
+  defer c.logDuration(ctx, "query", time.Now())
+  return retry(ctx, func() error {
+    db, err := c.db()
+    if err != nil {
+      return err
+    }
+    defer db.Close()
+
+    row := db.QueryRowContext(ctx, query, args...)
+    if row.Err() != nil {
+      return withPrincipal(ctx, db, row.Err())
+    }
+
+    return withPrincipal(ctx, db, scanner(row))
+  })
+}
+
+// transientErrors are the numbers of SQL errors that are retried, mostly raised by Azure SQL while a database
+// is moved, resumed or throttled.
+var transientErrors = map[int32]bool{
+  1205:  true, // The transaction was chosen as deadlock victim
+  4221:  true, // Login to read-secondary failed due to long wait on HADR_DATABASE_WAIT_FOR_TRANSITION_TO_VERSIONING
+  10928: true, // The resource limit of the database has been reached
+  10929: true, // The minimum guarantee of the database cannot be provided, as the server is too busy
+  40197: true, // The service has encountered an error processing the request
+  40501: true, // The service is currently busy
+  40613: true, // The database is not currently available
+  49918: true, // Cannot process request, not enough resources to process request
+  49919: true, // Cannot process create or update request, too many operations in progress
+  49920: true, // Cannot process request, too many operations in progress
+}
+
+// isTransient reports whether err is an SQL error in transientErrors or AdditionalTransientErrors.
+func (c *Connector) isTransient(err error) bool {
+  var sqlErr mssql.Error
+  if !errors.As(err, &sqlErr) {
+    return false
   }
-  defer db.Close()
+  if transientErrors[sqlErr.Number] {
+    return true
+  }
+  for _, number := range c.AdditionalTransientErrors {
+    if sqlErr.Number == number {
+      return true
+    }
+  }
+  return false
+}
 
-  row := db.QueryRowContext(ctx, query, args...)
-  if row.Err() != nil {
-    return row.Err()
+// retryTransient runs f until it succeeds, fails with an error that is not transient, or the timeout of the
+// connector is exceeded. Only reads are retried this way.
+func (c *Connector) retryTransient(ctx context.Context, f func() error) error {
+  deadline := time.Now().Add(c.Timeout)
+  delay := 250 * time.Millisecond
+  for {
+    err := f()
+    if err == nil || !c.isTransient(err) || time.Now().Add(delay).After(deadline) {
+      return err
+    }
+    log.Println(errors.Wrap(err, "retrying after transient error"))
+    select {
+    case <-ctx.Done():
+      return err
+    case <-time.After(delay):
+    }
+    if delay < 4*time.Second {
+      delay *= 2
+    }
   }
+}
+
+// runOnce runs f without retrying, for statements that are not safe to run twice.
+func runOnce(_ context.Context, f func() error) error {
+  return f()
+}
+
+// logDuration logs the duration of an SQL operation, including connecting, with the logger attached to the
+// context by the calling resource or data source. It logs nothing when debug logging is disabled.
+func (c *Connector) logDuration(ctx context.Context, operation string, start time.Time) {
+  zerolog.Ctx(ctx).Debug().
+    Str("operation", operation).
+    Str("database", c.Database).
+    Int64("duration_ms", time.Since(start).Milliseconds()).
+    Msg("SQL operation completed")
+}
+
+// missingObjectErrors are the numbers of SQL errors that are only raised when an object or its database does
+// not exist.
+var missingObjectErrors = map[int32]bool{
+  208:   true, // Invalid object name
+  911:   true, // Database does not exist
+  15517: true, // Cannot execute as the database principal because the principal does not exist
+}
 
-  return scanner(row)
+// IsMissingObjectError reports whether err is an SQL error in missingObjectErrors.
+func IsMissingObjectError(err error) bool {
+  var sqlErr mssql.Error
+  return errors.As(err, &sqlErr) && missingObjectErrors[sqlErr.Number]
+}
+
+// IsMissingDatabaseError reports whether err is SQL error 4060, raised when the database of the connection
+// does not exist, but also when the login has no access to it.
+func IsMissingDatabaseError(err error) bool {
+  var sqlErr mssql.Error
+  return errors.As(err, &sqlErr) && sqlErr.Number == 4060
+}
+
+// IsMissingPrincipalError reports whether err is SQL error 15151, raised when a principal does not exist, but
+// also when the connected principal has no permission on it.
+func IsMissingPrincipalError(err error) bool {
+  var sqlErr mssql.Error
+  return errors.As(err, &sqlErr) && sqlErr.Number == 15151
+}
+
+// permissionErrors are the numbers of SQL Server errors raised when the connected principal lacks permission.
+var permissionErrors = map[int32]bool{
+  229:   true, // The permission was denied on the object
+  230:   true, // The permission was denied on the column
+  262:   true, // The permission was denied in the database
+  297:   true, // The user does not have permission to perform this action
+  300:   true, // The permission was denied on the object or database
+  916:   true, // The server principal is not able to access the database under the current security context
+  15151: true, // Cannot find the principal, because it does not exist or you do not have permission
+  15247: true, // User does not have permission to perform this action
+}
+
+// withPrincipal adds the name of the connected principal to permission errors, as it is not always obvious
+// which identity the provider connects as, e.g. with managed identities or several provider configurations.
+func withPrincipal(ctx context.Context, db *sql.DB, err error) error {
+  var sqlErr mssql.Error
+  if err == nil || !errors.As(err, &sqlErr) || !permissionErrors[sqlErr.Number] {
+    return err
+  }
+  var principal string
+  if db.QueryRowContext(ctx, "SELECT SUSER_SNAME()").Scan(&principal) != nil {
+    return err
+  }
+  return errors.Wrapf(err, "connected as [%s]", principal)
 }
 
 func (c *Connector) db() (*sql.DB, error) {
@@ -163,31 +398,134 @@ func (c *Connector) db() (*sql.DB, error) {
   if err != nil {
     return nil, err
   }
-  if db, err := connectLoop(conn, c.Timeout); err != nil {
+  db, err := connectLoop(conn, c.Timeout, c.isTransient)
+  if err != nil {
+    return nil, err
+  }
+  if err = c.checkSession(db); err != nil {
+    db.Close()
     return nil, err
-  } else {
-    return db, nil
   }
+  c.KeepAlive.track(c)
+  return db, nil
+}
+
+// checkSession fails if SessionLanguage is not a language of the server, or sessions do not run as
+// ExecuteAsName. Sessions are only set to either if that can succeed, as a failing session init statement
+// would leave the driver with a bad connection. The check is repeated when the database changes, as users,
+// and the permission to impersonate them, are per database.
+func (c *Connector) checkSession(db *sql.DB) error {
+  if (c.SessionLanguage == "" && c.ExecuteAsName == "") || (c.sessionChecked && c.sessionCheckedDatabase == c.Database) {
+    return nil
+  }
+  var (
+    languageExists bool
+    impersonated   bool
+    principal      string
+  )
+  err := db.QueryRow(`SELECT CASE WHEN @language = '' OR EXISTS (SELECT 1 FROM [sys].[syslanguages] WHERE [name] = @language OR [alias] = @language) THEN 1 ELSE 0 END,
+                             CASE WHEN @executeAs = '' OR (@executeAsType = 'LOGIN' AND SUSER_SNAME() = @executeAs) OR (@executeAsType = 'USER' AND USER_NAME() = @executeAs) THEN 1 ELSE 0 END,
+                             ORIGINAL_LOGIN()`,
+    sql.Named("language", c.SessionLanguage),
+    sql.Named("executeAs", c.ExecuteAsName),
+    sql.Named("executeAsType", c.ExecuteAsType)).Scan(&languageExists, &impersonated, &principal)
+  if err != nil {
+    return err
+  }
+  if !languageExists {
+    return errors.Errorf("session_language [%s] is not a language of the server, see sys.syslanguages", c.SessionLanguage)
+  }
+  if !impersonated {
+    return errors.Errorf("unable to EXECUTE AS %s [%s] in database [%s]: the %s must exist, and [%s] needs IMPERSONATE permission on it",
+      c.ExecuteAsType, c.ExecuteAsName, c.Database, strings.ToLower(c.ExecuteAsType), principal)
+  }
+  c.sessionChecked = true
+  c.sessionCheckedDatabase = c.Database
+  return nil
+}
+
+// sessionInitSQL returns the statements that set SessionLanguage and SessionDateFormat on a session, and
+// impersonate ExecuteAsName. The driver resets the session before it is reused, which reverts the
+// impersonation, so no REVERT is needed.
+func (c *Connector) sessionInitSQL() string {
+  var stmts []string
+  if c.SessionLanguage != "" {
+    language := quoteString(c.SessionLanguage)
+    stmts = append(stmts, "IF EXISTS (SELECT 1 FROM [sys].[syslanguages] WHERE [name] = "+language+" OR [alias] = "+language+") SET LANGUAGE "+language)
+  }
+  if c.SessionDateFormat != "" {
+    // SessionDateFormat is validated by the provider to be one of the six orders of m, d and y
+    stmts = append(stmts, "SET DATEFORMAT "+c.SessionDateFormat)
+  }
+  if c.ExecuteAsName != "" {
+    // ExecuteAsType is validated by the provider to be LOGIN or USER
+    name := quoteString(c.ExecuteAsName)
+    stmts = append(stmts, "IF HAS_PERMS_BY_NAME("+name+", '"+c.ExecuteAsType+"', 'IMPERSONATE') = 1 EXECUTE AS "+c.ExecuteAsType+" = "+name)
+  }
+  return strings.Join(stmts, "; ")
+}
+
+// quoteString returns s as a Unicode string literal.
+func quoteString(s string) string {
+  return "N'" + strings.ReplaceAll(s, "'", "''") + "'"
 }
 
 func (c *Connector) connector() (driver.Connector, error) {
+  conn, err := c.newConnector()
+  if err != nil {
+    return nil, err
+  }
+  if mc, ok := conn.(*mssql.Connector); ok {
+    mc.SessionInitSQL = c.sessionInitSQL()
+  }
+  return conn, nil
+}
+
+func (c *Connector) newConnector() (driver.Connector, error) {
   query := url.Values{}
   host := fmt.Sprintf("%s:%s", c.Host, c.Port)
   if c.Database != "" {
     query.Set("database", c.Database)
   }
-  if c.Login != nil || c.AzureLogin != nil {
+  if c.ColumnEncryption {
+    query.Set("columnencryption", "true")
+  }
+  if c.HostNameInCertificate != "" {
+    query.Set("hostnameincertificate", c.HostNameInCertificate)
+  }
+  if c.TLSMinVersion != "" {
+    query.Set("tlsmin", c.TLSMinVersion)
+  }
+  if c.ServerCertificateThumbprint != "" {
+    // Encrypt the whole session, not only the login, when the certificate is pinned
+    query.Set("encrypt", "true")
+  }
+  if c.Login != nil || c.AzureLogin != nil || c.FedauthInteractive != nil {
     connectionString := (&url.URL{
       Scheme:   "sqlserver",
       User:     c.userPassword(),
       Host:     host,
       RawQuery: query.Encode(),
     }).String()
+    if c.ServerCertificateThumbprint != "" {
+      return c.pinnedConnector(connectionString)
+    }
     if c.Login != nil {
         return mssql.NewConnector(connectionString)
     }
+    if c.FedauthInteractive != nil {
+      config, err := msdsn.Parse(connectionString)
+      if err != nil {
+        return nil, err
+      }
+      return mssql.NewSecurityTokenConnector(config, c.interactiveToken)
+    }
     return mssql.NewAccessTokenConnector(connectionString, func() (string, error) { return c.tokenProvider() })
   }
+  if c.ServerCertificateThumbprint != "" {
+    // The azuread connectors parse their configuration internally, so their TLS configuration cannot be replaced
+    return nil, errors.New("server_certificate_thumbprint is not supported with azuread_default_chain_auth or azuread_managed_identity_auth")
+  }
   if c.FedauthMSI != nil {
     query.Set("fedauth", "ActiveDirectoryManagedIdentity")
     if c.FedauthMSI.UserID != "" {
@@ -204,6 +542,47 @@ func (c *Connector) connector() (driver.Connector, error) {
   return azuread.NewConnector(connectionString)
 }
 
+// pinnedConnector returns a connector that only accepts a server certificate with ServerCertificateThumbprint.
+// The thumbprint replaces validation against the trusted CAs, so that self-signed certificates can be pinned.
+func (c *Connector) pinnedConnector(connectionString string) (driver.Connector, error) {
+  config, err := msdsn.Parse(connectionString)
+  if err != nil {
+    return nil, err
+  }
+  thumbprint := strings.ToUpper(c.ServerCertificateThumbprint)
+  config.TLSConfig.InsecureSkipVerify = true
+  config.TLSConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+    return verifyThumbprint(rawCerts, thumbprint)
+  }
+  if c.Login != nil {
+    return mssql.NewConnectorConfig(config), nil
+  }
+  if c.FedauthInteractive != nil {
+    return mssql.NewSecurityTokenConnector(config, c.interactiveToken)
+  }
+  return mssql.NewSecurityTokenConnector(config, func(ctx context.Context) (string, error) { return c.tokenProvider() })
+}
+
+// verifyThumbprint fails unless the leaf certificate of rawCerts has the upper case hex thumbprint, which is
+// compared to its SHA-1 or SHA-256 hash depending on its length.
+func verifyThumbprint(rawCerts [][]byte, thumbprint string) error {
+  if len(rawCerts) == 0 {
+    return errors.New("server presented no certificate")
+  }
+  var actual string
+  if len(thumbprint) == 2*sha1.Size {
+    sum := sha1.Sum(rawCerts[0])
+    actual = strings.ToUpper(hex.EncodeToString(sum[:]))
+  } else {
+    sum := sha256.Sum256(rawCerts[0])
+    actual = strings.ToUpper(hex.EncodeToString(sum[:]))
+  }
+  if actual != thumbprint {
+    return errors.Errorf("server certificate thumbprint [%s] does not match server_certificate_thumbprint [%s]", actual, thumbprint)
+  }
+  return nil
+}
+
 func (c *Connector) userPassword() *url.Userinfo {
   if c.Login != nil {
     return url.UserPassword(c.Login.Username, c.Login.Password)
@@ -211,11 +590,25 @@ func (c *Connector) userPassword() *url.Userinfo {
   return nil
 }
 
-func (c *Connector) tokenProvider() (string, error) {
-  const resourceID = "https://database.windows.net/"
+// Azure clouds supported by azure_login. Tokens are only requested from the Active Directory endpoint of the selected cloud.
+var azureEnvironments = map[string]azure.Environment{
+  "public":       azure.PublicCloud,
+  "usgovernment": azure.USGovernmentCloud,
+  "china":        azure.ChinaCloud,
+}
 
+func (c *Connector) tokenProvider() (string, error) {
   admin := c.AzureLogin
-  oauthConfig, err := adal.NewOAuthConfig(azure.PublicCloud.ActiveDirectoryEndpoint, admin.TenantID)
+  environment, ok := azureEnvironments[admin.Environment]
+  if !ok {
+    if admin.Environment != "" {
+      return "", errors.Errorf("unknown azure environment [%s]", admin.Environment)
+    }
+    environment = azure.PublicCloud
+  }
+  resourceID := "https://" + environment.SQLDatabaseDNSSuffix + "/"
+
+  oauthConfig, err := adal.NewOAuthConfig(environment.ActiveDirectoryEndpoint, admin.TenantID)
   if err != nil {
     return "", err
   }
@@ -235,7 +628,86 @@ func (c *Connector) tokenProvider() (string, error) {
   return spt.OAuthToken(), nil
 }
 
-func connectLoop(connector driver.Connector, timeout time.Duration) (*sql.DB, error) {
+// interactiveLoginTimeout bounds the time a user has to complete an interactive login.
+const interactiveLoginTimeout = 5 * time.Minute
+
+// interactiveCredential is created once per Azure cloud, tenant and client for the run, so that the user logs in
+// once, and later connections are given the token it caches. Its lock makes parallel connections wait for the
+// first login instead of each starting one.
+type interactiveCredential struct {
+  sync.Mutex
+  credential azcore.TokenCredential
+}
+
+var (
+  interactiveCredentials     = make(map[FedauthInteractive]*interactiveCredential)
+  interactiveCredentialsLock sync.Mutex
+)
+
+// interactiveToken returns a token for azuread_interactive_auth, logging in with the browser, or with a device
+// code written to stderr, the first time it is called. Failures are reported as errors retrieving the access
+// token, so that connectLoop does not retry them and prompt the user again.
+func (c *Connector) interactiveToken(ctx context.Context) (string, error) {
+  admin := *c.FedauthInteractive
+  environment, ok := azureEnvironments[admin.Environment]
+  if !ok {
+    if admin.Environment != "" {
+      return "", errors.Errorf("unknown azure environment [%s]", admin.Environment)
+    }
+    environment = azure.PublicCloud
+  }
+
+  interactiveCredentialsLock.Lock()
+  cred, ok := interactiveCredentials[admin]
+  if !ok {
+    cred = &interactiveCredential{}
+    interactiveCredentials[admin] = cred
+  }
+  interactiveCredentialsLock.Unlock()
+
+  cred.Lock()
+  defer cred.Unlock()
+  if cred.credential == nil {
+    options := azcore.ClientOptions{Cloud: cloud.Configuration{ActiveDirectoryAuthorityHost: environment.ActiveDirectoryEndpoint}}
+    var err error
+    if admin.DeviceCode {
+      cred.credential, err = azidentity.NewDeviceCodeCredential(&azidentity.DeviceCodeCredentialOptions{
+        ClientOptions: options,
+        TenantID:      admin.TenantID,
+        ClientID:      admin.ClientID,
+        UserPrompt: func(ctx context.Context, message azidentity.DeviceCodeMessage) error {
+          // Terraform shows the stderr of providers in its log, where the [WARN] prefix sets the level
+          _, err := fmt.Fprintf(os.Stderr, "[WARN] azuread_interactive_auth: %s\n", message.Message)
+          return err
+        },
+      })
+    } else {
+      cred.credential, err = azidentity.NewInteractiveBrowserCredential(&azidentity.InteractiveBrowserCredentialOptions{
+        ClientOptions: options,
+        TenantID:      admin.TenantID,
+        ClientID:      admin.ClientID,
+      })
+    }
+    if err != nil {
+      return "", errors.Wrap(err, "error retrieving access token for azuread_interactive_auth")
+    }
+  }
+
+  ctx, cancel := context.WithTimeout(ctx, interactiveLoginTimeout)
+  defer cancel()
+  token, err := cred.credential.GetToken(ctx, policy.TokenRequestOptions{
+    Scopes: []string{"https://" + environment.SQLDatabaseDNSSuffix + "/.default"},
+  })
+  if err != nil {
+    if ctx.Err() == context.DeadlineExceeded {
+      return "", errors.Errorf("error retrieving access token for azuread_interactive_auth: the login was not completed within %s", interactiveLoginTimeout)
+    }
+    return "", errors.Wrap(err, "error retrieving access token for azuread_interactive_auth")
+  }
+  return token.Token, nil
+}
+
+func connectLoop(connector driver.Connector, timeout time.Duration, transient func(error) bool) (*sql.DB, error) {
   ticker := time.NewTicker(250 * time.Millisecond)
   defer ticker.Stop()
 
@@ -250,6 +722,16 @@ func connectLoop(connector driver.Connector, timeout time.Duration) (*sql.DB, er
       if err == nil {
         return db, nil
       }
+      if transient(err) {
+        log.Println(errors.Wrap(err, "failed to connect to database, retrying after transient error"))
+        continue
+      }
+      if IsMissingObjectError(err) || IsMissingDatabaseError(err) {
+        return nil, err
+      }
+      if certErr := certificateError(err); certErr != nil {
+        return nil, certErr
+      }
       if strings.Contains(err.Error(), "Login failed") {
         return nil, err
       }
@@ -264,6 +746,30 @@ func connectLoop(connector driver.Connector, timeout time.Duration) (*sql.DB, er
   }
 }
 
+// certificateErrors are parts of the messages of failures to validate the server certificate, with what to do
+// about each. The driver does not always wrap the TLS error, so the message is matched.
+var certificateErrors = []struct {
+  message  string
+  guidance string
+}{
+  {"does not match server_certificate_thumbprint", "if the certificate of the server was renewed, update server_certificate_thumbprint to the thumbprint of the new certificate"},
+  {"certificate is valid for", "set host_name_in_certificate to a name the certificate is valid for, or connect with that name as host"},
+  {"certificate signed by unknown authority", "add the CA that signed the certificate to the trusted CAs of the machine running Terraform, or pin the certificate with server_certificate_thumbprint, e.g. for a self-signed certificate"},
+  {"certificate has expired or is not yet valid", "renew the certificate of the server, or correct the clock of the machine running Terraform"},
+  {"x509: ", "fix the certificate of the server, or pin it with server_certificate_thumbprint"},
+}
+
+// certificateError returns err with guidance if it is a failure to validate the server certificate, and nil
+// otherwise. Such failures are not retried, and never cause a retry without validation.
+func certificateError(err error) error {
+  for _, e := range certificateErrors {
+    if strings.Contains(err.Error(), e.message) {
+      return errors.Wrapf(err, "the certificate of the server could not be validated: %s", e.guidance)
+    }
+  }
+  return nil
+}
+
 func connect(connector driver.Connector) (*sql.DB, error) {
   db := sql.OpenDB(connector)
   if err := db.Ping(); err != nil {
@@ -0,0 +1,40 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+)
+
+// GetTemporalHistoryRetention returns whether temporal history retention is enabled, or nil if the database does not exist.
+func (c *Connector) GetTemporalHistoryRetention(ctx context.Context, database string) (*bool, error) {
+  cmd := `SELECT is_temporal_history_retention_enabled FROM [sys].[databases] WHERE name = @database`
+  var enabled bool
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&enabled)
+      },
+      sql.Named("database", database),
+    )
+  if err != nil {
+    if err == sql.ErrNoRows {
+      return nil, nil
+    }
+    return nil, err
+  }
+  return &enabled, nil
+}
+
+func (c *Connector) SetTemporalHistoryRetention(ctx context.Context, database string, enabled bool) error {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'ALTER DATABASE ' + QuoteName(@database) + ' SET TEMPORAL_HISTORY_RETENTION ' +
+                     CASE WHEN @enabled = 1 THEN 'ON' ELSE 'OFF' END
+          EXEC (@sql)`
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd,
+      sql.Named("database", database),
+      sql.Named("enabled", enabled),
+    )
+}
@@ -3,13 +3,23 @@ package sql
 import (
   "context"
   "database/sql"
+  "fmt"
   "github.com/betr-io/terraform-provider-mssql/mssql/model"
   "strings"
 )
 
 func (c *Connector) GetUser(ctx context.Context, database, username string) (*model.User, error) {
   cmd := `DECLARE @stmt nvarchar(max)
-          IF @@VERSION LIKE 'Microsoft SQL Azure%'
+          IF SERVERPROPERTY('EngineEdition') IN (6, 11)
+            BEGIN
+              -- Azure Synapse Analytics does not support recursive CTEs, so only direct role memberships are read
+              SET @stmt = 'SELECT p.principal_id, p.name, p.authentication_type_desc, COALESCE(p.default_schema_name, ''''), COALESCE(p.default_language_name, ''''), p.sid, CONVERT(VARCHAR(1000), p.sid, 1) AS sidStr, '''', COALESCE(STRING_AGG(USER_NAME(r.role_principal_id), '',''), ''''), CONVERT(VARCHAR(33), p.modify_date, 126), COALESCE(@@SERVERNAME, CAST(SERVERPROPERTY(''ServerName'') AS nvarchar(128))) ' +
+                          'FROM [sys].[database_principals] p' +
+                          '  LEFT JOIN [sys].[database_role_members] r ON p.principal_id = r.member_principal_id ' +
+                          'WHERE p.name = ' + QuoteName(@username, '''') + ' ' +
+                          'GROUP BY p.principal_id, p.name, p.authentication_type_desc, p.default_schema_name, p.default_language_name, p.sid, p.modify_date'
+            END
+          ELSE IF @@VERSION LIKE 'Microsoft SQL Azure%'
             BEGIN
               SET @stmt = 'WITH CTE_Roles (principal_id, role_principal_id) AS ' +
                           '(' +
@@ -18,11 +28,11 @@ func (c *Connector) GetUser(ctx context.Context, database, username string) (*mo
                           '  SELECT member_principal_id, drm.role_principal_id FROM [sys].[database_role_members] drm' +
                           '    INNER JOIN CTE_Roles cr ON drm.member_principal_id = cr.role_principal_id' +
                           ') ' +
-                          'SELECT p.principal_id, p.name, p.authentication_type_desc, COALESCE(p.default_schema_name, ''''), COALESCE(p.default_language_name, ''''), p.sid, CONVERT(VARCHAR(1000), p.sid, 1) AS sidStr, '''', COALESCE(STRING_AGG(USER_NAME(r.role_principal_id), '',''), '''') ' +
+                          'SELECT p.principal_id, p.name, p.authentication_type_desc, COALESCE(p.default_schema_name, ''''), COALESCE(p.default_language_name, ''''), p.sid, CONVERT(VARCHAR(1000), p.sid, 1) AS sidStr, '''', COALESCE(STRING_AGG(USER_NAME(r.role_principal_id), '',''), ''''), CONVERT(VARCHAR(33), p.modify_date, 126), COALESCE(@@SERVERNAME, CAST(SERVERPROPERTY(''ServerName'') AS nvarchar(128))) ' +
                           'FROM [sys].[database_principals] p' +
                           '  LEFT JOIN CTE_Roles r ON p.principal_id = r.principal_id ' +
                           'WHERE p.name = ' + QuoteName(@username, '''') + ' ' +
-                          'GROUP BY p.principal_id, p.name, p.authentication_type_desc, p.default_schema_name, p.default_language_name, p.sid'
+                          'GROUP BY p.principal_id, p.name, p.authentication_type_desc, p.default_schema_name, p.default_language_name, p.sid, p.modify_date'
             END
           ELSE
             BEGIN
@@ -33,12 +43,12 @@ func (c *Connector) GetUser(ctx context.Context, database, username string) (*mo
                           '  SELECT member_principal_id, drm.role_principal_id FROM ' + QuoteName(@database) + '.[sys].[database_role_members] drm' +
                           '    INNER JOIN CTE_Roles cr ON drm.member_principal_id = cr.role_principal_id' +
                           ') ' +
-                          'SELECT p.principal_id, p.name, p.authentication_type_desc, COALESCE(p.default_schema_name, ''''), COALESCE(p.default_language_name, ''''), p.sid, CONVERT(VARCHAR(1000), p.sid, 1) AS sidStr, COALESCE(sl.name, ''''), COALESCE(STRING_AGG(USER_NAME(r.role_principal_id), '',''), '''') ' +
+                          'SELECT p.principal_id, p.name, p.authentication_type_desc, COALESCE(p.default_schema_name, ''''), COALESCE(p.default_language_name, ''''), p.sid, CONVERT(VARCHAR(1000), p.sid, 1) AS sidStr, COALESCE(sl.name, ''''), COALESCE(STRING_AGG(USER_NAME(r.role_principal_id), '',''), ''''), CONVERT(VARCHAR(33), p.modify_date, 126), COALESCE(@@SERVERNAME, CAST(SERVERPROPERTY(''ServerName'') AS nvarchar(128))) ' +
                           'FROM ' + QuoteName(@database) + '.[sys].[database_principals] p' +
                           '  LEFT JOIN CTE_Roles r ON p.principal_id = r.principal_id ' +
                           '  LEFT JOIN [master].[sys].[sql_logins] sl ON p.sid = sl.sid ' +
                           'WHERE p.name = ' + QuoteName(@username, '''') + ' ' +
-                          'GROUP BY p.principal_id, p.name, p.authentication_type_desc, p.default_schema_name, p.default_language_name, p.sid, sl.name'
+                          'GROUP BY p.principal_id, p.name, p.authentication_type_desc, p.default_schema_name, p.default_language_name, p.sid, p.modify_date, sl.name'
             END
           EXEC (@stmt)`
   var (
@@ -50,7 +60,7 @@ func (c *Connector) GetUser(ctx context.Context, database, username string) (*mo
     setDatabase(&database).
     QueryRowContext(ctx, cmd,
       func(r *sql.Row) error {
-        return r.Scan(&user.PrincipalID, &user.Username, &user.AuthType, &user.DefaultSchema, &user.DefaultLanguage, &sid, &user.SIDStr, &user.LoginName, &roles)
+        return r.Scan(&user.PrincipalID, &user.Username, &user.AuthType, &user.DefaultSchema, &user.DefaultLanguage, &sid, &user.SIDStr, &user.LoginName, &roles, &user.ModifyDate, &user.ServerName)
       },
       sql.Named("database", database),
       sql.Named("username", username),
@@ -82,8 +92,10 @@ func (c *Connector) GetUser(ctx context.Context, database, username string) (*mo
   return &user, nil
 }
 
-func (c *Connector) CreateUser(ctx context.Context, database string, user *model.User) error {
-  cmd := `DECLARE @stmt nvarchar(max)
+// CreateUser creates the user and adds it to its roles, and returns the executed statements, one per line, with
+// the password redacted.
+func (c *Connector) CreateUser(ctx context.Context, database string, user *model.User) (string, error) {
+  stmt := `DECLARE @stmt nvarchar(max)
           DECLARE @language nvarchar(max) = @defaultLanguage
           IF @language = '' SET @language = NULL
           IF @authType = 'INSTANCE'
@@ -95,14 +107,14 @@ func (c *Connector) CreateUser(ctx context.Context, database string, user *model
             BEGIN
               SET @stmt = 'CREATE USER ' + QuoteName(@username) + ' WITH PASSWORD = ' + QuoteName(@password, '''') + ', ' +
                           'DEFAULT_SCHEMA = ' + QuoteName(@defaultSchema)
-              IF NOT @@VERSION LIKE 'Microsoft SQL Azure%'
+              IF NOT (@@VERSION LIKE 'Microsoft SQL Azure%' OR @@VERSION LIKE 'Microsoft Azure SQL Data Warehouse%')
                 BEGIN
                   SET @stmt = @stmt + ', DEFAULT_LANGUAGE = ' + Coalesce(QuoteName(@language), 'NONE')
                 END
             END
           IF @authType = 'EXTERNAL'
             BEGIN
-              IF @@VERSION LIKE 'Microsoft SQL Azure%'
+              IF @@VERSION LIKE 'Microsoft SQL Azure%' OR @@VERSION LIKE 'Microsoft Azure SQL Data Warehouse%'
                 BEGIN
                   IF @objectId != ''
                     BEGIN
@@ -120,7 +132,9 @@ func (c *Connector) CreateUser(ctx context.Context, database string, user *model
                               'DEFAULT_LANGUAGE = ' + Coalesce(QuoteName(@language), 'NONE')
                 END
             END
-
+          DECLARE @ddl nvarchar(max) = CASE WHEN @password = '' THEN @stmt ELSE REPLACE(@stmt, QuoteName(@password, ''''), '''***''') END
+          `
+  cmd := stmt + `
           BEGIN TRANSACTION;
           EXEC sp_getapplock @Resource = 'create_func', @LockMode = 'Exclusive';
           IF exists (select compatibility_level FROM sys.databases where name = db_name() and compatibility_level < 130) AND objectproperty(object_id('String_Split'), 'isProcedure') IS NULL
@@ -161,21 +175,34 @@ func (c *Connector) CreateUser(ctx context.Context, database string, user *model
                       '    DECLARE @sql nvarchar(max);' +
                       '    SET @sql = ''ALTER ROLE '' + QuoteName(@role) + '' ADD MEMBER ' + QuoteName(@username) + ''';' +
                       '    EXEC (@sql);' +
+                      '    INSERT INTO #applied (stmt) VALUES (@sql);' +
                       '    FETCH NEXT FROM role_cur INTO @role;' +
                       '  END;' +
                       'CLOSE role_cur;' +
                       'DEALLOCATE role_cur;'
-          EXEC (@stmt)`
+          CREATE TABLE #applied (id int IDENTITY(1, 1), stmt nvarchar(max))
+          EXEC (@stmt)
+          SELECT stmt FROM (SELECT 0 AS id, @ddl AS stmt UNION ALL SELECT id, stmt FROM #applied) s ORDER BY id`
   if user.AuthType != "EXTERNAL" {
     // External users do not have a server login
     _, err := c.GetLogin(ctx, user.LoginName)
     if err != nil {
-      return err
+      return "", err
     }
   }
-  return c.
-    setDatabase(&database).
-    ExecContext(ctx, cmd,
+  synapse, err := c.setDatabase(&database).isSynapse(ctx)
+  if err != nil {
+    return "", err
+  }
+  if synapse {
+    // Azure Synapse Analytics supports neither cursors nor ALTER ROLE ... ADD MEMBER
+    cmd = stmt + `
+          EXEC (@stmt)
+          SELECT @ddl`
+  }
+  var applied []string
+  err = c.
+    execQueryContext(ctx, cmd, scanAppliedStatements(&applied),
       sql.Named("database", database),
       sql.Named("username", user.Username),
       sql.Named("objectId", user.ObjectId),
@@ -186,20 +213,28 @@ func (c *Connector) CreateUser(ctx context.Context, database string, user *model
       sql.Named("defaultLanguage", user.DefaultLanguage),
       sql.Named("roles", strings.Join(user.Roles, ",")),
     )
+  if err != nil || !synapse {
+    return strings.Join(applied, "\n"), err
+  }
+  roleStmts, err := c.setUserRolesSynapse(ctx, user.Username, user.Roles)
+  return strings.Join(append(applied, roleStmts...), "\n"), err
 }
 
-func (c *Connector) UpdateUser(ctx context.Context, database string, user *model.User) error {
-  cmd := `DECLARE @stmt nvarchar(max)
+// UpdateUser alters the user and its role memberships, and returns the executed statements, one per line.
+func (c *Connector) UpdateUser(ctx context.Context, database string, user *model.User) (string, error) {
+  stmt := `DECLARE @stmt nvarchar(max)
           SET @stmt = 'ALTER USER ' + QuoteName(@username) + ' '
           DECLARE @language nvarchar(max) = @defaultLanguage
           IF @language = '' SET @language = NULL
           SET @stmt = @stmt + 'WITH DEFAULT_SCHEMA = ' + QuoteName(@defaultSchema)
           DECLARE @auth_type nvarchar(max) = (SELECT authentication_type_desc FROM [sys].[database_principals] WHERE name = @username)
-          IF NOT @@VERSION LIKE 'Microsoft SQL Azure%' AND @auth_type != 'INSTANCE'
+          IF NOT (@@VERSION LIKE 'Microsoft SQL Azure%' OR @@VERSION LIKE 'Microsoft Azure SQL Data Warehouse%') AND @auth_type != 'INSTANCE'
             BEGIN
               SET @stmt = @stmt + ', DEFAULT_LANGUAGE = ' + Coalesce(QuoteName(@language), 'NONE')
             END
-
+          DECLARE @ddl nvarchar(max) = @stmt
+          `
+  cmd := stmt + `
           BEGIN TRANSACTION;
           EXEC sp_getapplock @Resource = 'create_func', @LockMode = 'Exclusive';
           IF exists (select compatibility_level FROM sys.databases where name = db_name() and compatibility_level < 130) AND objectproperty(object_id('String_Split'), 'isProcedure') IS NULL
@@ -241,6 +276,7 @@ func (c *Connector) UpdateUser(ctx context.Context, database string, user *model
                       '  BEGIN' +
                       '    SET @sql = ''ALTER ROLE '' + QuoteName(@role) + '' DROP MEMBER ' + QuoteName(@username) + ''';' +
                       '    EXEC (@sql);' +
+                      '    INSERT INTO #applied (stmt) VALUES (@sql);' +
                       '    FETCH NEXT FROM del_role_cur INTO @role;' +
                       '  END;' +
                       'CLOSE del_role_cur;' +
@@ -251,20 +287,85 @@ func (c *Connector) UpdateUser(ctx context.Context, database string, user *model
                       '  BEGIN' +
                       '    SET @sql = ''ALTER ROLE '' + QuoteName(@role) + '' ADD MEMBER ' + QuoteName(@username) + ''';' +
                       '    EXEC (@sql);' +
+                      '    INSERT INTO #applied (stmt) VALUES (@sql);' +
                       '    FETCH NEXT FROM add_role_cur INTO @role;' +
                       '  END;' +
                       'CLOSE add_role_cur;' +
                       'DEALLOCATE add_role_cur;'
-          EXEC (@stmt)`
-  return c.
-    setDatabase(&database).
-    ExecContext(ctx, cmd,
+          CREATE TABLE #applied (id int IDENTITY(1, 1), stmt nvarchar(max))
+          EXEC (@stmt)
+          SELECT stmt FROM (SELECT 0 AS id, @ddl AS stmt UNION ALL SELECT id, stmt FROM #applied) s ORDER BY id`
+  synapse, err := c.setDatabase(&database).isSynapse(ctx)
+  if err != nil {
+    return "", err
+  }
+  if synapse {
+    cmd = stmt + `
+          EXEC (@stmt)
+          SELECT @ddl`
+  }
+  var applied []string
+  err = c.
+    execQueryContext(ctx, cmd, scanAppliedStatements(&applied),
       sql.Named("database", database),
       sql.Named("username", user.Username),
       sql.Named("defaultSchema", user.DefaultSchema),
       sql.Named("defaultLanguage", user.DefaultLanguage),
       sql.Named("roles", strings.Join(user.Roles, ",")),
     )
+  if err != nil || !synapse {
+    return strings.Join(applied, "\n"), err
+  }
+  roleStmts, err := c.setUserRolesSynapse(ctx, user.Username, user.Roles)
+  return strings.Join(append(applied, roleStmts...), "\n"), err
+}
+
+// setUserRolesSynapse makes the user a member of exactly the given roles using sp_addrolemember and
+// sp_droprolemember, as Azure Synapse Analytics supports neither cursors nor ALTER ROLE ... ADD MEMBER, and
+// returns the executed statements. The connector must already be set to the user's database.
+func (c *Connector) setUserRolesSynapse(ctx context.Context, username string, roles []string) ([]string, error) {
+  cmd := `DECLARE @sql nvarchar(max), @applied nvarchar(max)
+          SELECT @sql = STRING_AGG(CAST(s.stmt AS nvarchar(max)), '; '),
+                 @applied = STRING_AGG(CAST(s.stmt AS nvarchar(max)), CHAR(10)) FROM (
+            SELECT 'EXEC sp_droprolemember ' + QuoteName(r.name, '''') + ', ' + QuoteName(@username, '''') AS stmt
+              FROM [sys].[database_role_members] drm
+              INNER JOIN [sys].[database_principals] r ON drm.role_principal_id = r.principal_id
+              WHERE drm.member_principal_id = DATABASE_PRINCIPAL_ID(@username)
+                AND r.name COLLATE SQL_Latin1_General_CP1_CI_AS NOT IN (SELECT value FROM STRING_SPLIT(@roles, ','))
+            UNION ALL
+            SELECT 'EXEC sp_addrolemember ' + QuoteName(r.name, '''') + ', ' + QuoteName(@username, '''') AS stmt
+              FROM [sys].[database_principals] r
+              WHERE r.type = 'R' AND r.name != 'public'
+                AND r.name COLLATE SQL_Latin1_General_CP1_CI_AS IN (SELECT value FROM STRING_SPLIT(@roles, ','))
+                AND NOT EXISTS (SELECT 1 FROM [sys].[database_role_members] drm
+                                WHERE drm.role_principal_id = r.principal_id AND drm.member_principal_id = DATABASE_PRINCIPAL_ID(@username))
+          ) s
+          IF @sql IS NOT NULL EXEC (@sql)
+          SELECT COALESCE(@applied, '')`
+  var applied string
+  err := c.execQueryRowContext(ctx, cmd,
+    func(r *sql.Row) error {
+      return r.Scan(&applied)
+    },
+    sql.Named("username", username), sql.Named("roles", strings.Join(roles, ",")))
+  if err != nil || applied == "" {
+    return nil, err
+  }
+  return strings.Split(applied, "\n"), nil
+}
+
+// scanAppliedStatements reads the statements returned by CreateUser and UpdateUser, one per row.
+func scanAppliedStatements(applied *[]string) func(*sql.Rows) error {
+  return func(r *sql.Rows) error {
+    for r.Next() {
+      var stmt string
+      if err := r.Scan(&stmt); err != nil {
+        return err
+      }
+      *applied = append(*applied, stmt)
+    }
+    return r.Err()
+  }
 }
 
 func (c *Connector) DeleteUser(ctx context.Context, database, username string) error {
@@ -277,6 +378,197 @@ func (c *Connector) DeleteUser(ctx context.Context, database, username string) e
     ExecContext(ctx, cmd, sql.Named("database", database), sql.Named("username", username))
 }
 
+// GetUserOwnedSecurables lists the schemas, objects and roles owned by the user, which prevent it from being dropped.
+func (c *Connector) GetUserOwnedSecurables(ctx context.Context, database, username string) ([]string, error) {
+  cmd := `DECLARE @principalId int = DATABASE_PRINCIPAL_ID(@username)
+          SELECT 'SCHEMA::' + QuoteName(name) FROM [sys].[schemas] WHERE principal_id = @principalId
+          UNION ALL
+          SELECT 'OBJECT::' + QuoteName(SCHEMA_NAME(schema_id)) + '.' + QuoteName(name) FROM [sys].[objects] WHERE principal_id = @principalId
+          UNION ALL
+          SELECT 'ROLE::' + QuoteName(name) FROM [sys].[database_principals] WHERE owning_principal_id = @principalId AND type = 'R'`
+  var securables []string
+  err := c.
+    setDatabase(&database).
+    QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        var securable string
+        if err := r.Scan(&securable); err != nil {
+          return err
+        }
+        securables = append(securables, securable)
+      }
+      return r.Err()
+    }, sql.Named("username", username))
+  if err != nil {
+    return nil, err
+  }
+  return securables, nil
+}
+
+// ReassignUserOwnership transfers ownership of the schemas, objects and roles owned by the user to owner, and
+// returns the executed statements.
+func (c *Connector) ReassignUserOwnership(ctx context.Context, database, username, owner string) (string, error) {
+  cmd := `DECLARE @principalId int = DATABASE_PRINCIPAL_ID(@username)
+          DECLARE @sql nvarchar(max) = ''
+          IF @principalId IS NOT NULL
+            BEGIN
+              SELECT @sql = @sql + 'ALTER AUTHORIZATION ON SCHEMA::' + QuoteName(name) + ' TO ' + QuoteName(@owner) + ';'
+                FROM [sys].[schemas] WHERE principal_id = @principalId
+              SELECT @sql = @sql + 'ALTER AUTHORIZATION ON OBJECT::' + QuoteName(SCHEMA_NAME(schema_id)) + '.' + QuoteName(name) + ' TO ' + QuoteName(@owner) + ';'
+                FROM [sys].[objects] WHERE principal_id = @principalId
+              SELECT @sql = @sql + 'ALTER AUTHORIZATION ON ROLE::' + QuoteName(name) + ' TO ' + QuoteName(@owner) + ';'
+                FROM [sys].[database_principals] WHERE owning_principal_id = @principalId AND type = 'R'
+              EXEC (@sql)
+            END
+          SELECT @sql`
+  var applied string
+  err := c.
+    setDatabase(&database).
+    execQueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&applied)
+      },
+      sql.Named("username", username), sql.Named("owner", owner))
+  return applied, err
+}
+
+// RevokeUserGrants removes the user from its roles, and revokes the permissions it has granted to other
+// principals, which prevent it from being dropped. Permissions granted by the user are revoked with CASCADE
+// and AS the user, so they are removed as recorded, along with any permissions granted on from them. The
+// executed statements are returned.
+func (c *Connector) RevokeUserGrants(ctx context.Context, database, username string) (string, error) {
+  cmd := `DECLARE @principalId int = DATABASE_PRINCIPAL_ID(@username)
+          DECLARE @sql nvarchar(max) = ''
+          IF @principalId IS NOT NULL
+            BEGIN
+              SELECT @sql = @sql + 'ALTER ROLE ' + QuoteName(USER_NAME(role_principal_id)) + ' DROP MEMBER ' + QuoteName(@username) + ';'
+                FROM [sys].[database_role_members] WHERE member_principal_id = @principalId
+              SELECT @sql = @sql + 'REVOKE ' + p.permission_name +
+                            CASE p.class
+                              WHEN 0 THEN ''
+                              WHEN 1 THEN ' ON OBJECT::' + QuoteName(OBJECT_SCHEMA_NAME(p.major_id)) + '.' + QuoteName(OBJECT_NAME(p.major_id)) +
+                                          CASE WHEN p.minor_id > 0 THEN '(' + QuoteName(COL_NAME(p.major_id, p.minor_id)) + ')' ELSE '' END
+                              WHEN 3 THEN ' ON SCHEMA::' + QuoteName(SCHEMA_NAME(p.major_id))
+                              WHEN 4 THEN ' ON ' + CASE dp.type WHEN 'R' THEN 'ROLE' WHEN 'A' THEN 'APPLICATION ROLE' ELSE 'USER' END + '::' + QuoteName(dp.name)
+                              WHEN 6 THEN ' ON TYPE::' + QuoteName(SCHEMA_NAME(t.schema_id)) + '.' + QuoteName(t.name)
+                            END +
+                            ' FROM ' + QuoteName(USER_NAME(p.grantee_principal_id)) + ' CASCADE AS ' + QuoteName(@username) + ';'
+                FROM [sys].[database_permissions] p
+                  LEFT JOIN [sys].[database_principals] dp ON p.class = 4 AND dp.principal_id = p.major_id
+                  LEFT JOIN [sys].[types] t ON p.class = 6 AND t.user_type_id = p.major_id
+                WHERE p.grantor_principal_id = @principalId AND p.grantee_principal_id != @principalId AND p.class IN (0, 1, 3, 4, 6)
+              EXEC (@sql)
+            END
+          SELECT @sql`
+  var applied string
+  err := c.
+    setDatabase(&database).
+    execQueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&applied)
+      },
+      sql.Named("username", username))
+  return applied, err
+}
+
+// GetUserNameByPrincipalID returns the current name of the user with the principal id and SID, or an empty
+// string if no such user exists, e.g. to detect that a user was renamed.
+func (c *Connector) GetUserNameByPrincipalID(ctx context.Context, database string, principalID int64, sid string) (string, error) {
+  cmd := `SELECT name FROM [sys].[database_principals]
+          WHERE principal_id = @principalId AND type NOT IN ('R', 'A') AND (@sid = '' OR CONVERT(VARCHAR(1000), sid, 1) = @sid)`
+  var name string
+  err := c.
+    setDatabase(&database).
+    QueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&name)
+      },
+      sql.Named("principalId", principalID),
+      sql.Named("sid", sid),
+    )
+  if err == sql.ErrNoRows {
+    return "", nil
+  }
+  return name, err
+}
+
+// RenameUser renames the user, and returns the executed statement. The default schema, role memberships and
+// permissions of the user are kept, as they refer to its principal id.
+func (c *Connector) RenameUser(ctx context.Context, database, username, newUsername string) (string, error) {
+  cmd := `DECLARE @sql nvarchar(max)
+          SET @sql = 'ALTER USER ' + QuoteName(@username) + ' WITH NAME = ' + QuoteName(@newUsername)
+          EXEC (@sql)
+          SELECT @sql`
+  var applied string
+  err := c.
+    setDatabase(&database).
+    execQueryRowContext(ctx, cmd,
+      func(r *sql.Row) error {
+        return r.Scan(&applied)
+      },
+      sql.Named("username", username),
+      sql.Named("newUsername", newUsername),
+    )
+  return applied, err
+}
+
+// VerifyUserAccess impersonates the user and returns the access it is missing, i.e. access to and CONNECT
+// permission on the database, and membership of roles.
+func (c *Connector) VerifyUserAccess(ctx context.Context, database, username string, roles []string) ([]string, error) {
+  // The roles are listed before impersonating the user, who may lack permission on a dbo.String_Split shim in
+  // databases below compatibility level 130
+  args := []interface{}{sql.Named("username", username)}
+  var insertRoles strings.Builder
+  for i, role := range roles {
+    if role == "" {
+      continue
+    }
+    fmt.Fprintf(&insertRoles, "INSERT @roles VALUES (@role%d)\n", i)
+    args = append(args, sql.Named(fmt.Sprintf("role%d", i), role))
+  }
+  cmd := `DECLARE @problems TABLE (problem nvarchar(max))
+          DECLARE @roles TABLE (name nvarchar(128))
+          ` + insertRoles.String() + `
+          EXECUTE AS USER = @username
+          BEGIN TRY
+            IF ISNULL(HAS_DBACCESS(DB_NAME()), 0) = 0
+              INSERT @problems VALUES ('no access to database')
+            IF ISNULL(HAS_PERMS_BY_NAME(DB_NAME(), 'DATABASE', 'CONNECT'), 0) = 0
+              INSERT @problems VALUES ('no CONNECT permission on database')
+            INSERT @problems
+              SELECT 'not a member of role ' + QuoteName(name) FROM @roles
+              WHERE ISNULL(IS_ROLEMEMBER(name), 0) = 0
+          END TRY
+          BEGIN CATCH
+            REVERT;
+            THROW;
+          END CATCH
+          REVERT
+          SELECT problem FROM @problems`
+  return c.
+    setDatabase(&database).
+    queryProblems(ctx, cmd, args...)
+}
+
+// queryProblems runs a query returning one problem per row.
+func (c *Connector) queryProblems(ctx context.Context, cmd string, args ...interface{}) ([]string, error) {
+  var problems []string
+  err := c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+    for r.Next() {
+      var problem string
+      if err := r.Scan(&problem); err != nil {
+        return err
+      }
+      problems = append(problems, problem)
+    }
+    return r.Err()
+  }, args...)
+  if err != nil {
+    return nil, err
+  }
+  return problems, nil
+}
+
 func (c *Connector) setDatabase(database *string) *Connector {
   if *database == "" {
     *database = "master"
@@ -0,0 +1,159 @@
+package sql
+
+import (
+  "context"
+  "database/sql"
+  "github.com/betr-io/terraform-provider-mssql/mssql/model"
+  "sort"
+  "strings"
+)
+
+// GetUsers lists the users of database that are mapped to a server login, with their direct role memberships.
+// Fixed principals and the user of the connection itself are not listed. The logins are read from master in a
+// second query, as Azure SQL Database cannot reference master from a user database.
+func (c *Connector) GetUsers(ctx context.Context, database string) ([]*model.User, error) {
+  cmd := `SELECT p.principal_id, p.name, COALESCE(p.default_schema_name, ''), CONVERT(VARCHAR(1000), p.sid, 1),
+                 COALESCE((SELECT STRING_AGG(r.name, ',') FROM [sys].[database_role_members] drm
+                             INNER JOIN [sys].[database_principals] r ON drm.role_principal_id = r.principal_id
+                           WHERE drm.member_principal_id = p.principal_id), '')
+          FROM [sys].[database_principals] p
+          WHERE p.authentication_type_desc = 'INSTANCE' AND p.type IN ('S', 'U', 'G') AND p.principal_id > 4
+            AND p.principal_id != DATABASE_PRINCIPAL_ID()
+          ORDER BY p.name`
+  users := make([]*model.User, 0)
+  err := c.
+    setDatabase(&database).
+    QueryContext(ctx, cmd, func(r *sql.Rows) error {
+      for r.Next() {
+        user := &model.User{AuthType: "INSTANCE"}
+        var roles string
+        if err := r.Scan(&user.PrincipalID, &user.Username, &user.DefaultSchema, &user.SIDStr, &roles); err != nil {
+          return err
+        }
+        user.Roles = make([]string, 0)
+        if roles != "" {
+          user.Roles = strings.Split(roles, ",")
+          sort.Strings(user.Roles)
+        }
+        users = append(users, user)
+      }
+      return r.Err()
+    })
+  if err != nil || len(users) == 0 {
+    return users, err
+  }
+
+  cmd = `SELECT CONVERT(VARCHAR(1000), sid, 1), name FROM [sys].[server_principals] WHERE type IN ('S', 'U', 'G', 'E', 'X')`
+  logins := make(map[string]string)
+  c.Database = "master"
+  err = c.QueryContext(ctx, cmd, func(r *sql.Rows) error {
+    for r.Next() {
+      var sid, name string
+      if err := r.Scan(&sid, &name); err != nil {
+        return err
+      }
+      logins[sid] = name
+    }
+    return r.Err()
+  })
+  if err != nil {
+    return nil, err
+  }
+  for _, user := range users {
+    user.LoginName = logins[user.SIDStr]
+  }
+  return users, nil
+}
+
+// SetUsers creates, alters and, if dropUnlisted is set, drops users of database in a single transaction, so that
+// current ends up as desired. Users and roles are matched case insensitively. The executed statements are
+// returned.
+func (c *Connector) SetUsers(ctx context.Context, database string, current, desired []*model.User, dropUnlisted bool) ([]string, error) {
+  stmts := userStatements(current, desired, dropUnlisted)
+  if len(stmts) == 0 {
+    return stmts, nil
+  }
+  cmd := "SET XACT_ABORT ON;\nBEGIN TRANSACTION;\n" + strings.Join(stmts, ";\n") + ";\nCOMMIT TRANSACTION;"
+  return stmts, c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd)
+}
+
+// DropUsers drops the users of database that exist, in a single transaction.
+func (c *Connector) DropUsers(ctx context.Context, database string, usernames []string) error {
+  var stmts []string
+  for _, username := range usernames {
+    stmts = append(stmts, "IF DATABASE_PRINCIPAL_ID("+quoteString(username)+") IS NOT NULL DROP USER "+quoteName(username))
+  }
+  if len(stmts) == 0 {
+    return nil
+  }
+  cmd := "SET XACT_ABORT ON;\nBEGIN TRANSACTION;\n" + strings.Join(stmts, ";\n") + ";\nCOMMIT TRANSACTION;"
+  return c.
+    setDatabase(&database).
+    ExecContext(ctx, cmd)
+}
+
+func userStatements(current, desired []*model.User, dropUnlisted bool) []string {
+  existing := make(map[string]*model.User)
+  for _, user := range current {
+    existing[strings.ToLower(user.Username)] = user
+  }
+  var stmts []string
+  for _, user := range desired {
+    name := quoteName(user.Username)
+    cur, ok := existing[strings.ToLower(user.Username)]
+    delete(existing, strings.ToLower(user.Username))
+    var currentRoles []string
+    if !ok {
+      stmts = append(stmts, "CREATE USER "+name+" FOR LOGIN "+quoteName(user.LoginName)+" WITH DEFAULT_SCHEMA = "+quoteName(user.DefaultSchema))
+    } else {
+      var options []string
+      if !strings.EqualFold(cur.DefaultSchema, user.DefaultSchema) {
+        options = append(options, "DEFAULT_SCHEMA = "+quoteName(user.DefaultSchema))
+      }
+      if !strings.EqualFold(cur.LoginName, user.LoginName) {
+        options = append(options, "LOGIN = "+quoteName(user.LoginName))
+      }
+      if len(options) > 0 {
+        stmts = append(stmts, "ALTER USER "+name+" WITH "+strings.Join(options, ", "))
+      }
+      currentRoles = cur.Roles
+    }
+    for _, role := range currentRoles {
+      if !containsFold(user.Roles, role) {
+        stmts = append(stmts, "ALTER ROLE "+quoteName(role)+" DROP MEMBER "+name)
+      }
+    }
+    for _, role := range user.Roles {
+      if !containsFold(currentRoles, role) {
+        stmts = append(stmts, "ALTER ROLE "+quoteName(role)+" ADD MEMBER "+name)
+      }
+    }
+  }
+  if dropUnlisted {
+    var unlisted []string
+    for _, user := range existing {
+      unlisted = append(unlisted, user.Username)
+    }
+    sort.Strings(unlisted)
+    for _, username := range unlisted {
+      stmts = append(stmts, "DROP USER "+quoteName(username))
+    }
+  }
+  return stmts
+}
+
+func containsFold(values []string, value string) bool {
+  for _, v := range values {
+    if strings.EqualFold(v, value) {
+      return true
+    }
+  }
+  return false
+}
+
+// quoteName returns name as a delimited identifier, as QUOTENAME does.
+func quoteName(name string) string {
+  return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}